@@ -0,0 +1,360 @@
+//
+// Package analysis assembles Lucene-style analysis chains around the stemmer:
+// char filters rewrite the text, a tokenizer splits it into tokens and token
+// filters rewrite or drop the tokens. The offsets of the tokens always refer
+// to the text given to Analyze, whatever the char filters did.
+//
+// Components are registered by name and an Analyzer can be configured in
+// JSON:
+//
+//    {
+//      "char_filters": [{"type": "html_strip"}],
+//      "tokenizer": {"type": "standard"},
+//      "filters": [
+//        {"type": "lowercase"},
+//        {"type": "possessive"},
+//        {"type": "stop", "list": "snowball"},
+//        {"type": "porter_stem"},
+//        {"type": "length", "min": 2}
+//      ]
+//    }
+//
+// The components are described in charfilter.go and filter.go.
+//
+package analysis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/pigi72333/stemmer/tokenizer"
+)
+
+//
+// Token is a token of an analyzed text.
+//
+type Token struct {
+	Term string
+	// Start and End are the byte offsets of the token in the analyzed text.
+	Start, End int
+	// PositionIncrement is the distance in positions from the previous token,
+	// or from the start for the first one: it is 1 for tokens that follow
+	// each other, more when tokens were removed in between and 0 for tokens at
+	// the same position.
+	PositionIncrement int
+	Type              tokenizer.Type
+}
+
+//
+// CharFilter rewrites a text before it is tokenized, returning the offsets
+// mapping the result back to the text.
+//
+type CharFilter interface {
+	FilterChars(text string) (string, *Offsets)
+}
+
+//
+// Tokenizer splits a text into tokens.
+//
+type Tokenizer interface {
+	Tokenize(text string) []Token
+}
+
+//
+// TokenFilter rewrites tokens. It may modify tokens in place and returns the
+// tokens left.
+//
+type TokenFilter interface {
+	FilterTokens(tokens []Token) []Token
+}
+
+//
+// Offsets maps the offsets of a filtered text to the offsets in the text it
+// was filtered from.
+//
+type Offsets struct {
+	out  []int
+	diff []int
+	// endDiff differs from diff where text was removed: a token starting
+	// there starts after the text removed, but one ending there ends before.
+	endDiff []int
+}
+
+//
+// Record records that offset out of the filtered text is offset in of the
+// original text, and so are the offsets after it up to the next one
+// recorded, shifted by the same amount. Offsets must be recorded in
+// increasing order.
+//
+func (o *Offsets) Record(out, in int) {
+	if n := len(o.out); n > 0 && o.out[n-1] == out {
+		o.diff[n-1] = in - out
+		o.endDiff[n-1] = in - out
+		return
+	}
+	o.out = append(o.out, out)
+	o.diff = append(o.diff, in-out)
+	o.endDiff = append(o.endDiff, in-out)
+}
+
+//
+// RecordRemoval records that the original text from inStart to inEnd was
+// removed at offset out of the filtered text.
+//
+func (o *Offsets) RecordRemoval(out, inStart, inEnd int) {
+	if n := len(o.out); n > 0 && o.out[n-1] == out {
+		o.diff[n-1] = inEnd - out
+		return
+	}
+	o.out = append(o.out, out)
+	o.diff = append(o.diff, inEnd-out)
+	o.endDiff = append(o.endDiff, inStart-out)
+}
+
+//
+// Correct returns the offset in the original text of the start offset off. A
+// nil Offsets returns off.
+//
+func (o *Offsets) Correct(off int) int {
+	if o == nil {
+		return off
+	}
+	i := sort.SearchInts(o.out, off+1)
+	if i == 0 {
+		return off
+	}
+	return off + o.diff[i-1]
+}
+
+//
+// CorrectEnd is Correct for end offsets.
+//
+func (o *Offsets) CorrectEnd(off int) int {
+	if o == nil {
+		return off
+	}
+	i := sort.SearchInts(o.out, off+1)
+	if i == 0 {
+		return off
+	}
+	if o.out[i-1] == off {
+		return off + o.endDiff[i-1]
+	}
+	return off + o.diff[i-1]
+}
+
+//
+// Analyzer is an analysis chain.
+//
+type Analyzer struct {
+	CharFilters []CharFilter
+	Tokenizer   Tokenizer
+	Filters     []TokenFilter
+}
+
+//
+// Analyze returns the tokens of text.
+//
+func (a *Analyzer) Analyze(text string) []Token {
+	offsets := make([]*Offsets, len(a.CharFilters))
+	for i, f := range a.CharFilters {
+		text, offsets[i] = f.FilterChars(text)
+	}
+	tokens := a.Tokenizer.Tokenize(text)
+	for i := range tokens {
+		for j := len(offsets) - 1; j >= 0; j-- {
+			tokens[i].Start = offsets[j].Correct(tokens[i].Start)
+			tokens[i].End = offsets[j].CorrectEnd(tokens[i].End)
+		}
+	}
+	for _, f := range a.Filters {
+		tokens = f.FilterTokens(tokens)
+	}
+	return tokens
+}
+
+//
+// Terms returns the terms of the tokens of text.
+//
+func (a *Analyzer) Terms(text string) []string {
+	tokens := a.Analyze(text)
+	terms := make([]string, len(tokens))
+	for i, tok := range tokens {
+		terms[i] = tok.Term
+	}
+	return terms
+}
+
+//
+// Config is the JSON configuration of an Analyzer. Each component is an
+// object with the registered name of the component in "type" and its
+// parameters in the other fields.
+//
+type Config struct {
+	CharFilters []json.RawMessage `json:"char_filters"`
+	Tokenizer   json.RawMessage   `json:"tokenizer"`
+	Filters     []json.RawMessage `json:"filters"`
+}
+
+//
+// ParseConfig returns the Analyzer configured by the JSON data.
+//
+func ParseConfig(data []byte) (*Analyzer, error) {
+	var c Config
+	if err := decodeParams(data, &c); err != nil {
+		return nil, fmt.Errorf("analysis: %v", err)
+	}
+	return NewAnalyzer(c)
+}
+
+//
+// NewAnalyzer returns the Analyzer configured by c.
+//
+func NewAnalyzer(c Config) (*Analyzer, error) {
+	a := &Analyzer{}
+	for i, raw := range c.CharFilters {
+		name, err := componentName(raw)
+		if err != nil {
+			return nil, fmt.Errorf("analysis: char filter %d: %v", i, err)
+		}
+		f, ok := lookup(charFilters, name)
+		if !ok {
+			return nil, fmt.Errorf("analysis: char filter %d: unknown type %q", i, name)
+		}
+		cf, err := f.(func(json.RawMessage) (CharFilter, error))(raw)
+		if err != nil {
+			return nil, fmt.Errorf("analysis: char filter %d (%s): %v", i, name, err)
+		}
+		a.CharFilters = append(a.CharFilters, cf)
+	}
+
+	if c.Tokenizer == nil {
+		c.Tokenizer = json.RawMessage(`{"type": "standard"}`)
+	}
+	name, err := componentName(c.Tokenizer)
+	if err != nil {
+		return nil, fmt.Errorf("analysis: tokenizer: %v", err)
+	}
+	f, ok := lookup(tokenizers, name)
+	if !ok {
+		return nil, fmt.Errorf("analysis: tokenizer: unknown type %q", name)
+	}
+	if a.Tokenizer, err = f.(func(json.RawMessage) (Tokenizer, error))(c.Tokenizer); err != nil {
+		return nil, fmt.Errorf("analysis: tokenizer (%s): %v", name, err)
+	}
+
+	for i, raw := range c.Filters {
+		name, err := componentName(raw)
+		if err != nil {
+			return nil, fmt.Errorf("analysis: filter %d: %v", i, err)
+		}
+		f, ok := lookup(tokenFilters, name)
+		if !ok {
+			return nil, fmt.Errorf("analysis: filter %d: unknown type %q", i, name)
+		}
+		tf, err := f.(func(json.RawMessage) (TokenFilter, error))(raw)
+		if err != nil {
+			return nil, fmt.Errorf("analysis: filter %d (%s): %v", i, name, err)
+		}
+		a.Filters = append(a.Filters, tf)
+	}
+	return a, nil
+}
+
+//
+// English returns the usual chain for English text: the standard tokenizer,
+// then lowercase, possessive, stop with the Snowball list and porter_stem.
+//
+func English() *Analyzer {
+	a, err := ParseConfig([]byte(`{"filters": [
+		{"type": "lowercase"},
+		{"type": "possessive"},
+		{"type": "stop", "list": "snowball"},
+		{"type": "porter_stem"}
+	]}`))
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+var (
+	registryMu   sync.RWMutex
+	charFilters  = make(map[string]interface{})
+	tokenizers   = make(map[string]interface{})
+	tokenFilters = make(map[string]interface{})
+)
+
+//
+// RegisterCharFilter makes a char filter available by name to the
+// configurations. factory builds the filter from the JSON object configuring
+// it. It panics if factory is nil or if a char filter is registered twice
+// with the same name.
+//
+func RegisterCharFilter(name string, factory func(params json.RawMessage) (CharFilter, error)) {
+	register(charFilters, "char filter", name, factory, factory == nil)
+}
+
+//
+// RegisterTokenizer is RegisterCharFilter for tokenizers.
+//
+func RegisterTokenizer(name string, factory func(params json.RawMessage) (Tokenizer, error)) {
+	register(tokenizers, "tokenizer", name, factory, factory == nil)
+}
+
+//
+// RegisterTokenFilter is RegisterCharFilter for token filters.
+//
+func RegisterTokenFilter(name string, factory func(params json.RawMessage) (TokenFilter, error)) {
+	register(tokenFilters, "token filter", name, factory, factory == nil)
+}
+
+func register(m map[string]interface{}, kind, name string, factory interface{}, isNil bool) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if isNil {
+		panic("analysis: Register " + kind + " is nil")
+	}
+	if _, dup := m[name]; dup {
+		panic("analysis: Register called twice for " + kind + " " + name)
+	}
+	m[name] = factory
+}
+
+func lookup(m map[string]interface{}, name string) (interface{}, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := m[name]
+	return f, ok
+}
+
+//
+// componentType is embedded in the parameters of the components, which are
+// decoded from the whole object.
+//
+type componentType struct {
+	Type string `json:"type"`
+}
+
+func componentName(raw json.RawMessage) (string, error) {
+	var c componentType
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return "", err
+	}
+	if c.Type == "" {
+		return "", fmt.Errorf("missing type")
+	}
+	return c.Type, nil
+}
+
+//
+// decodeParams decodes the JSON data into v, rejecting unknown fields.
+//
+func decodeParams(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
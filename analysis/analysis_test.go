@@ -0,0 +1,118 @@
+package analysis
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pigi72333/stemmer/tokenizer"
+)
+
+func TestEnglish(t *testing.T) {
+	text := "The ponies' riders were running to John's races."
+
+	expected := []Token{
+		{Term: "poni", Start: 4, End: 10, PositionIncrement: 2, Type: tokenizer.Word},
+		{Term: "rider", Start: 12, End: 18, PositionIncrement: 1, Type: tokenizer.Word},
+		{Term: "run", Start: 24, End: 31, PositionIncrement: 2, Type: tokenizer.Word},
+		{Term: "john", Start: 35, End: 41, PositionIncrement: 2, Type: tokenizer.Word},
+		{Term: "race", Start: 42, End: 47, PositionIncrement: 1, Type: tokenizer.Word},
+	}
+
+	if tokens := English().Analyze(text); !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("Analyzer.Analyze() return value not what was expected, pass: '%s' return: '%+v' expected: '%+v'", text, tokens, expected)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	a, err := ParseConfig([]byte(`{
+		"char_filters": [
+			{"type": "html_strip"},
+			{"type": "mapping", "mappings": {"&": " and "}}
+		],
+		"tokenizer": {"type": "standard"},
+		"filters": [
+			{"type": "lowercase"},
+			{"type": "stop", "list": "lucene", "words": ["nice"]},
+			{"type": "porter_stem", "stemmer": "porter2"},
+			{"type": "length", "min": 3, "max": 8},
+			{"type": "dedupe"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("ParseConfig() returned an error: '%v'", err)
+	}
+
+	text := "<p>Nice <b>Cats</b> &amp; generously-sized dogs</p><script>var x;</script>ok"
+	expected := []Token{
+		{Term: "cat", Start: 11, End: 15, PositionIncrement: 2, Type: tokenizer.Word},
+		{Term: "dog", Start: 43, End: 47, PositionIncrement: 3, Type: tokenizer.Word},
+	}
+
+	tokens := a.Analyze(text)
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("Analyzer.Analyze() return value not what was expected, pass: '%s' return: '%+v' expected: '%+v'", text, tokens, expected)
+	}
+}
+
+func TestCharFilterOffsets(t *testing.T) {
+	a, err := ParseConfig([]byte(`{
+		"char_filters": [
+			{"type": "html_strip"},
+			{"type": "mapping", "mappings": {"colour": "color"}}
+		],
+		"tokenizer": {"type": "whitespace"}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseConfig() returned an error: '%v'", err)
+	}
+
+	text := "<div>caf&eacute;</div><!-- x --><i>colours</i> fish&amp;chips"
+	terms := []string{"café", "colors", "fish&chips"}
+	originals := []string{"caf&eacute;", "colours", "fish&amp;chips"}
+
+	tokens := a.Analyze(text)
+	if len(tokens) != len(terms) {
+		t.Fatalf("Analyzer.Analyze() return value not what was expected, pass: '%s' return: '%+v'", text, tokens)
+	}
+	for k, tok := range tokens {
+		if tok.Term != terms[k] || text[tok.Start:tok.End] != originals[k] {
+			t.Errorf("Analyzer.Analyze() token not what was expected, return: '%s' at '%s' expected: '%s' at '%s'", tok.Term, text[tok.Start:tok.End], terms[k], originals[k])
+		}
+	}
+}
+
+func TestDedupeFilter(t *testing.T) {
+	tokens := []Token{
+		{Term: "run", PositionIncrement: 1},
+		{Term: "run", PositionIncrement: 0},
+		{Term: "race", PositionIncrement: 0},
+		{Term: "run", PositionIncrement: 1},
+	}
+
+	var terms []string
+	for _, tok := range (DedupeFilter{}).FilterTokens(tokens) {
+		terms = append(terms, tok.Term)
+	}
+	if expected := []string{"run", "race", "run"}; !reflect.DeepEqual(terms, expected) {
+		t.Errorf("DedupeFilter.FilterTokens() return value not what was expected, return: '%q' expected: '%q'", terms, expected)
+	}
+}
+
+func TestParseConfigError(t *testing.T) {
+	fixtures := []string{
+		`{"filters": [{"type": "stem"}]}`,
+		`{"filters": [{"type": "stop", "list": "french"}]}`,
+		`{"filters": [{"type": "length", "min": 5, "max": 2}]}`,
+		`{"filters": [{"type": "lowercase", "locale": "tr"}]}`,
+		`{"filters": [{"min": 2}]}`,
+		`{"tokenizer": {"type": "ngram"}}`,
+		`{"analyzer": "english"}`,
+	}
+
+	for _, value := range fixtures {
+		if _, err := ParseConfig([]byte(value)); err == nil || !strings.HasPrefix(err.Error(), "analysis: ") {
+			t.Errorf("ParseConfig() error not what was expected, pass: '%s' return: '%v'", value, err)
+		}
+	}
+}
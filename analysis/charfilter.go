@@ -0,0 +1,236 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+//
+// The char filters are:
+//
+//    html_strip            removes the HTML tags, comments, scripts and
+//                          styles, replacing the tags that break a line or a
+//                          block with a newline, and decodes the character
+//                          references
+//    mapping               replaces strings by others, longest first:
+//                          {"type": "mapping", "mappings": {"&": " and "}}
+//
+
+func init() {
+	RegisterCharFilter("html_strip", func(params json.RawMessage) (CharFilter, error) {
+		var p componentType
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return HTMLStrip{}, nil
+	})
+	RegisterCharFilter("mapping", func(params json.RawMessage) (CharFilter, error) {
+		var p struct {
+			componentType
+			Mappings map[string]string `json:"mappings"`
+		}
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return NewMapping(p.Mappings)
+	})
+}
+
+//
+// HTMLStrip is the html_strip char filter.
+//
+type HTMLStrip struct{}
+
+var htmlBlockTags = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "br": true,
+	"dd": true, "div": true, "dl": true, "dt": true, "footer": true, "h1": true,
+	"h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "header": true,
+	"hr": true, "li": true, "main": true, "nav": true, "ol": true, "p": true,
+	"pre": true, "section": true, "table": true, "td": true, "th": true,
+	"title": true, "tr": true, "ul": true,
+}
+
+var htmlEntities = map[string]string{
+	"amp": "&", "lt": "<", "gt": ">", "quot": "\"", "apos": "'", "nbsp": " ",
+	"ndash": "–", "mdash": "—", "lsquo": "‘", "rsquo": "’", "ldquo": "“",
+	"rdquo": "”", "hellip": "…", "copy": "©", "reg": "®", "eacute": "é",
+}
+
+func (HTMLStrip) FilterChars(text string) (string, *Offsets) {
+	var b strings.Builder
+	offsets := &Offsets{}
+	i := 0
+	for i < len(text) {
+		switch text[i] {
+		case '<':
+			end, block := htmlTagEnd(text, i)
+			if end < 0 {
+				break
+			}
+			if block {
+				out := b.Len()
+				b.WriteByte('\n')
+				recordReplacement(offsets, out, b.Len(), i, end)
+			} else {
+				offsets.RecordRemoval(b.Len(), i, end)
+			}
+			i = end
+			continue
+		case '&':
+			if s, end := htmlReference(text, i); end > 0 {
+				out := b.Len()
+				b.WriteString(s)
+				recordReplacement(offsets, out, b.Len(), i, end)
+				i = end
+				continue
+			}
+		}
+		b.WriteByte(text[i])
+		i++
+	}
+	return b.String(), offsets
+}
+
+//
+// htmlTagEnd returns the end of the tag, comment, script or style starting at
+// text[i], or -1 if there is none, and whether it breaks a block.
+//
+func htmlTagEnd(text string, i int) (int, bool) {
+	rest := text[i:]
+	if strings.HasPrefix(rest, "<!--") {
+		if end := strings.Index(rest[4:], "-->"); end >= 0 {
+			return i + 4 + end + 3, false
+		}
+		return len(text), false
+	}
+	end := strings.IndexByte(rest, '>')
+	if end < 0 || len(rest) < 2 {
+		return -1, false
+	}
+	tag := strings.TrimPrefix(rest[1:end], "/")
+	n := 0
+	for n < len(tag) && (isASCIILetter(tag[n]) || '0' <= tag[n] && tag[n] <= '9' || n == 0 && tag[n] == '!') {
+		n++
+	}
+	if n == 0 || !isASCIILetter(tag[0]) && tag[0] != '!' {
+		return -1, false
+	}
+	name := strings.ToLower(tag[:n])
+	if (name == "script" || name == "style") && rest[1] != '/' {
+		if closing := strings.Index(strings.ToLower(rest), "</"+name); closing >= 0 {
+			if closingEnd := strings.IndexByte(rest[closing:], '>'); closingEnd >= 0 {
+				return i + closing + closingEnd + 1, true
+			}
+		}
+		return len(text), true
+	}
+	return i + end + 1, htmlBlockTags[name]
+}
+
+func isASCIILetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+//
+// htmlReference decodes the character reference starting at text[i],
+// returning its end, or 0 if there is none.
+//
+func htmlReference(text string, i int) (string, int) {
+	end := strings.IndexByte(text[i:], ';')
+	if end < 2 || end > 10 {
+		return "", 0
+	}
+	name := text[i+1 : i+end]
+	if name[0] == '#' {
+		var n int64
+		var err error
+		if len(name) > 1 && (name[1] == 'x' || name[1] == 'X') {
+			n, err = strconv.ParseInt(name[2:], 16, 32)
+		} else {
+			n, err = strconv.ParseInt(name[1:], 10, 32)
+		}
+		if err != nil || !utf8.ValidRune(rune(n)) {
+			return "", 0
+		}
+		return string(rune(n)), i + end + 1
+	}
+	if s, ok := htmlEntities[name]; ok {
+		return s, i + end + 1
+	}
+	return "", 0
+}
+
+//
+// Mapping is the mapping char filter.
+//
+type Mapping struct {
+	// from are the strings to replace, longest first, and to their
+	// replacements.
+	from []string
+	to   []string
+}
+
+//
+// NewMapping returns a Mapping replacing the keys of mappings by their
+// values.
+//
+func NewMapping(mappings map[string]string) (*Mapping, error) {
+	m := &Mapping{}
+	for from := range mappings {
+		if from == "" {
+			return nil, fmt.Errorf("empty string mapped to %q", mappings[from])
+		}
+		m.from = append(m.from, from)
+	}
+	sort.Slice(m.from, func(i, j int) bool {
+		if len(m.from[i]) != len(m.from[j]) {
+			return len(m.from[i]) > len(m.from[j])
+		}
+		return m.from[i] < m.from[j]
+	})
+	for _, from := range m.from {
+		m.to = append(m.to, mappings[from])
+	}
+	return m, nil
+}
+
+func (m *Mapping) FilterChars(text string) (string, *Offsets) {
+	var b strings.Builder
+	offsets := &Offsets{}
+	i := 0
+next:
+	for i < len(text) {
+		for k, from := range m.from {
+			if strings.HasPrefix(text[i:], from) {
+				out := b.Len()
+				b.WriteString(m.to[k])
+				recordReplacement(offsets, out, b.Len(), i, i+len(from))
+				i += len(from)
+				continue next
+			}
+		}
+		b.WriteByte(text[i])
+		i++
+	}
+	return b.String(), offsets
+}
+
+//
+// recordReplacement records the offsets of text[inStart:inEnd] replaced by
+// the output from outStart to outEnd. The offsets inside the replacement stay
+// inside the text it replaces.
+//
+func recordReplacement(offsets *Offsets, outStart, outEnd, inStart, inEnd int) {
+	for k := 1; outStart+k < outEnd; k++ {
+		in := inStart + k
+		if in > inEnd {
+			in = inEnd
+		}
+		offsets.Record(outStart+k, in)
+	}
+	offsets.Record(outEnd, inEnd)
+}
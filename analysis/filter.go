@@ -0,0 +1,292 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pigi72333/stemmer"
+	"github.com/pigi72333/stemmer/stopwords"
+	"github.com/pigi72333/stemmer/tokenizer"
+)
+
+//
+// The tokenizers are:
+//
+//    standard              the tokens of the tokenizer package, without the
+//                          punctuation
+//    whitespace            the runs of characters that are not spaces, all
+//                          typed as words
+//
+// The token filters are:
+//
+//    lowercase             puts the terms in lower case
+//    possessive            removes the English possessive 's of the words
+//    stop                  removes the stop words: "list" names one of the
+//                          lists of the stopwords package (snowball, smart or
+//                          lucene), "words" adds words, "file" a list file,
+//                          and "stemmed" looks the terms up among the stems of
+//                          the list, for a filter that runs after stemming
+//    porter_stem           replaces the words by their stem with Stem, or with
+//                          the registered stemmer named by "stemmer"
+//    length                removes the terms with less than "min" or more
+//                          than "max" letters
+//    dedupe                removes the tokens with the same term at the same
+//                          position
+//
+
+func init() {
+	RegisterTokenizer("standard", func(params json.RawMessage) (Tokenizer, error) {
+		var p componentType
+		return StandardTokenizer{}, decodeParams(params, &p)
+	})
+	RegisterTokenizer("whitespace", func(params json.RawMessage) (Tokenizer, error) {
+		var p componentType
+		return WhitespaceTokenizer{}, decodeParams(params, &p)
+	})
+
+	RegisterTokenFilter("lowercase", func(params json.RawMessage) (TokenFilter, error) {
+		var p componentType
+		return LowercaseFilter{}, decodeParams(params, &p)
+	})
+	RegisterTokenFilter("possessive", func(params json.RawMessage) (TokenFilter, error) {
+		var p componentType
+		return PossessiveFilter{}, decodeParams(params, &p)
+	})
+	RegisterTokenFilter("stop", func(params json.RawMessage) (TokenFilter, error) {
+		var p struct {
+			componentType
+			List    string   `json:"list"`
+			Words   []string `json:"words"`
+			File    string   `json:"file"`
+			Stemmed bool     `json:"stemmed"`
+		}
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		var list *stopwords.List
+		switch p.List {
+		case "snowball":
+			list = stopwords.Snowball()
+		case "smart":
+			list = stopwords.SMART()
+		case "lucene":
+			list = stopwords.Lucene()
+		case "":
+			list = stopwords.NewList()
+		default:
+			return nil, fmt.Errorf("unknown list %q", p.List)
+		}
+		if p.File != "" {
+			l, err := stopwords.Load(p.File)
+			if err != nil {
+				return nil, err
+			}
+			list.Add(l.Words()...)
+		}
+		list.Add(p.Words...)
+		return StopFilter{Filter: stopwords.StopFilter{List: list, Stemmed: p.Stemmed}}, nil
+	})
+	RegisterTokenFilter("porter_stem", func(params json.RawMessage) (TokenFilter, error) {
+		var p struct {
+			componentType
+			Stemmer string `json:"stemmer"`
+		}
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if p.Stemmer == "" {
+			return StemFilter{Stemmer: stemmer.Porter{}}, nil
+		}
+		s, ok := stemmer.Lookup(p.Stemmer)
+		if !ok {
+			return nil, fmt.Errorf("unknown stemmer %q", p.Stemmer)
+		}
+		return StemFilter{Stemmer: s}, nil
+	})
+	RegisterTokenFilter("length", func(params json.RawMessage) (TokenFilter, error) {
+		var p struct {
+			componentType
+			LengthFilter
+		}
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if p.Min < 0 || p.Max < 0 || p.Max > 0 && p.Max < p.Min {
+			return nil, fmt.Errorf("bad lengths, min %d max %d", p.Min, p.Max)
+		}
+		return p.LengthFilter, nil
+	})
+	RegisterTokenFilter("dedupe", func(params json.RawMessage) (TokenFilter, error) {
+		var p componentType
+		return DedupeFilter{}, decodeParams(params, &p)
+	})
+}
+
+//
+// StandardTokenizer is the standard tokenizer.
+//
+type StandardTokenizer struct{}
+
+func (StandardTokenizer) Tokenize(text string) []Token {
+	var tokens []Token
+	t := tokenizer.New(text)
+	for tok, ok := t.Next(); ok; tok, ok = t.Next() {
+		if tok.Type == tokenizer.Punctuation {
+			continue
+		}
+		tokens = append(tokens, Token{
+			Term:              tok.Text,
+			Start:             tok.Start,
+			End:               tok.End,
+			PositionIncrement: 1,
+			Type:              tok.Type,
+		})
+	}
+	return tokens
+}
+
+//
+// WhitespaceTokenizer is the whitespace tokenizer.
+//
+type WhitespaceTokenizer struct{}
+
+func (WhitespaceTokenizer) Tokenize(text string) []Token {
+	var tokens []Token
+	start := -1
+	for i, r := range text + " " {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				tokens = append(tokens, Token{Term: text[start:i], Start: start, End: i, PositionIncrement: 1})
+			}
+			start = -1
+		} else if start < 0 {
+			start = i
+		}
+	}
+	return tokens
+}
+
+//
+// LowercaseFilter is the lowercase filter.
+//
+type LowercaseFilter struct{}
+
+func (LowercaseFilter) FilterTokens(tokens []Token) []Token {
+	for i := range tokens {
+		tokens[i].Term = strings.ToLower(tokens[i].Term)
+	}
+	return tokens
+}
+
+//
+// PossessiveFilter is the possessive filter.
+//
+type PossessiveFilter struct{}
+
+func (PossessiveFilter) FilterTokens(tokens []Token) []Token {
+	for i := range tokens {
+		if tokens[i].Type != tokenizer.Word {
+			continue
+		}
+		term := tokens[i].Term
+		for _, s := range []string{"'s", "’s", "'S", "’S"} {
+			if len(term) > len(s) && strings.HasSuffix(term, s) {
+				tokens[i].Term = term[:len(term)-len(s)]
+				break
+			}
+		}
+	}
+	return tokens
+}
+
+//
+// StopFilter is the stop filter.
+//
+type StopFilter struct {
+	Filter stopwords.StopFilter
+}
+
+func (f StopFilter) FilterTokens(tokens []Token) []Token {
+	return remove(tokens, func(tok Token) bool {
+		return tok.Type == tokenizer.Word && f.Filter.Stop(tok.Term)
+	})
+}
+
+//
+// StemFilter is the porter_stem filter.
+//
+type StemFilter struct {
+	Stemmer stemmer.Stemmer
+}
+
+func (f StemFilter) FilterTokens(tokens []Token) []Token {
+	for i := range tokens {
+		if tokens[i].Type == tokenizer.Word {
+			tokens[i].Term = f.Stemmer.StemString(tokens[i].Term)
+		}
+	}
+	return tokens
+}
+
+//
+// LengthFilter is the length filter. A Max of 0 is no maximum.
+//
+type LengthFilter struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+func (f LengthFilter) FilterTokens(tokens []Token) []Token {
+	return remove(tokens, func(tok Token) bool {
+		n := utf8.RuneCountInString(tok.Term)
+		return n < f.Min || f.Max > 0 && n > f.Max
+	})
+}
+
+//
+// DedupeFilter is the dedupe filter.
+//
+type DedupeFilter struct{}
+
+func (DedupeFilter) FilterTokens(tokens []Token) []Token {
+	kept := tokens[:0]
+	// seen are the terms at the position of the last token kept.
+	var seen []string
+next:
+	for _, tok := range tokens {
+		if tok.PositionIncrement > 0 {
+			seen = seen[:0]
+		} else {
+			for _, term := range seen {
+				if term == tok.Term {
+					continue next
+				}
+			}
+		}
+		seen = append(seen, tok.Term)
+		kept = append(kept, tok)
+	}
+	return kept
+}
+
+//
+// remove removes the tokens for which drop is true, adding their position
+// increments to the next token kept.
+//
+func remove(tokens []Token, drop func(Token) bool) []Token {
+	kept := tokens[:0]
+	increment := 0
+	for _, tok := range tokens {
+		if drop(tok) {
+			increment += tok.PositionIncrement
+			continue
+		}
+		tok.PositionIncrement += increment
+		increment = 0
+		kept = append(kept, tok)
+	}
+	return kept
+}
@@ -0,0 +1,312 @@
+package stemmer
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//
+// BatchOptions configure StemBatch and StemPipeline. The zero value stems
+// with Stem on GOMAXPROCS goroutines.
+//
+type BatchOptions struct {
+	// Stemmer stems the words; Stem is used if it is nil.
+	Stemmer Stemmer
+	// Workers is the number of goroutines stemming, GOMAXPROCS if it is 0
+	// or less.
+	Workers int
+	// ChunkSize is the number of words a worker takes at a time, 1024 if
+	// it is 0 or less.
+	ChunkSize int
+	// Progress, if not nil, is called every ProgressInterval, one second
+	// if it is 0 or less, and once at the end, with the words stemmed so
+	// far. It is called from a single goroutine.
+	Progress         func(BatchStats)
+	ProgressInterval time.Duration
+}
+
+//
+// BatchStats report the throughput of StemBatch and StemPipeline.
+//
+type BatchStats struct {
+	Words   int64
+	Elapsed time.Duration
+}
+
+//
+// WordsPerSecond returns the words stemmed per second.
+//
+func (s BatchStats) WordsPerSecond() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Words) / s.Elapsed.Seconds()
+}
+
+func (o *BatchOptions) workers() int {
+	if o.Workers > 0 {
+		return o.Workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+func (o *BatchOptions) chunkSize() int {
+	if o.ChunkSize > 0 {
+		return o.ChunkSize
+	}
+	return 1024
+}
+
+//
+// stemChunk returns the stems of words. With Stem the stems share a single
+// buffer, so a chunk costs a few allocations instead of one per word.
+//
+func (o *BatchOptions) stemChunk(words [][]byte, stems [][]byte) {
+	if o.Stemmer != nil {
+		for i, word := range words {
+			stems[i] = o.Stemmer.Stem(word)
+		}
+		return
+	}
+	size := 0
+	for _, word := range words {
+		size += len(word)
+	}
+	buf := make([]byte, 0, size)
+	for i, word := range words {
+		start := len(buf)
+		buf = AppendStem(buf, word)
+		stems[i] = buf[start:len(buf):len(buf)]
+	}
+}
+
+//
+// batchMeter counts the words stemmed and reports them to Progress.
+//
+type batchMeter struct {
+	words    int64
+	start    time.Time
+	progress func(BatchStats)
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func newBatchMeter(o *BatchOptions) *batchMeter {
+	m := &batchMeter{start: time.Now(), progress: o.Progress}
+	if m.progress == nil {
+		return m
+	}
+	interval := o.ProgressInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	m.stop, m.done = make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(m.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.progress(m.stats())
+			case <-m.stop:
+				return
+			}
+		}
+	}()
+	return m
+}
+
+func (m *batchMeter) add(n int) {
+	atomic.AddInt64(&m.words, int64(n))
+}
+
+func (m *batchMeter) stats() BatchStats {
+	return BatchStats{Words: atomic.LoadInt64(&m.words), Elapsed: time.Since(m.start)}
+}
+
+//
+// finish stops the reports and returns the final stats, reporting them too.
+//
+func (m *batchMeter) finish() BatchStats {
+	if m.progress != nil {
+		close(m.stop)
+		<-m.done
+	}
+	stats := m.stats()
+	if m.progress != nil {
+		m.progress(stats)
+	}
+	return stats
+}
+
+//
+// StemBatch returns the stems of words, in the same order, stemmed by
+// opts.Workers goroutines taking opts.ChunkSize words at a time. If ctx is
+// done before all the words are stemmed, StemBatch returns the stems of the
+// chunks that were, nil for the others, and ctx.Err().
+//
+func StemBatch(ctx context.Context, words [][]byte, opts BatchOptions) ([][]byte, BatchStats, error) {
+	stems := make([][]byte, len(words))
+	meter := newBatchMeter(&opts)
+	size := opts.chunkSize()
+
+	chunks := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < opts.workers(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range chunks {
+				end := start + size
+				if end > len(words) {
+					end = len(words)
+				}
+				opts.stemChunk(words[start:end], stems[start:end])
+				meter.add(end - start)
+			}
+		}()
+	}
+
+	var err error
+feed:
+	for start := 0; start < len(words); start += size {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		select {
+		case chunks <- start:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break feed
+		}
+	}
+	close(chunks)
+	wg.Wait()
+	return stems, meter.finish(), err
+}
+
+//
+// batchChunk is a chunk of the words of StemPipeline, numbered in the order
+// they were read.
+//
+type batchChunk struct {
+	seq   int
+	words [][]byte
+	stems [][]byte
+}
+
+//
+// StemPipeline stems the words received from in and sends their stems, in
+// the same order, on the channel it returns, which is closed once in is
+// closed and all the stems are sent, or when ctx is done. The words are
+// grouped into chunks of up to opts.ChunkSize, without waiting for more
+// words than in has ready, and stemmed by opts.Workers goroutines; at most
+// two chunks per worker are in progress at a time.
+//
+// The stats, sent once the output is closed, are those of the words whose
+// stems were sent. The caller must not modify a word after sending it.
+//
+func StemPipeline(ctx context.Context, in <-chan []byte, opts BatchOptions) (<-chan []byte, <-chan BatchStats) {
+	out := make(chan []byte, opts.chunkSize())
+	stats := make(chan BatchStats, 1)
+	meter := newBatchMeter(&opts)
+	workers := opts.workers()
+	size := opts.chunkSize()
+
+	// Every chunk takes a slot until its stems are sent.
+	slots := make(chan struct{}, 2*workers)
+	jobs := make(chan *batchChunk)
+	results := make(chan *batchChunk)
+
+	// The reader groups the words into chunks.
+	go func() {
+		defer close(jobs)
+		for seq := 0; ; seq++ {
+			var words [][]byte
+			select {
+			case word, ok := <-in:
+				if !ok {
+					return
+				}
+				words = append(make([][]byte, 0, size), word)
+			case <-ctx.Done():
+				return
+			}
+		more:
+			for len(words) < size {
+				select {
+				case word, ok := <-in:
+					if !ok {
+						break more
+					}
+					words = append(words, word)
+				default:
+					break more
+				}
+			}
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- &batchChunk{seq: seq, words: words}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range jobs {
+				c.stems = make([][]byte, len(c.words))
+				opts.stemChunk(c.words, c.stems)
+				select {
+				case results <- c:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// The writer sends the stems of the chunks in order.
+	go func() {
+		defer func() {
+			close(out)
+			stats <- meter.finish()
+			close(stats)
+		}()
+		pending := make(map[int]*batchChunk)
+		next := 0
+		for c := range results {
+			pending[c.seq] = c
+			for c, ok := pending[next]; ok; c, ok = pending[next] {
+				delete(pending, next)
+				for _, stem := range c.stems {
+					select {
+					case out <- stem:
+					case <-ctx.Done():
+						return
+					}
+				}
+				meter.add(len(c.stems))
+				<-slots
+				next++
+			}
+		}
+	}()
+	return out, stats
+}
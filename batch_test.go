@@ -0,0 +1,185 @@
+package stemmer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+//
+// vocStems returns the words of voc.txt and their stems in output.txt.
+//
+func vocStems(tb testing.TB) (words, stems [][]byte) {
+	for _, w := range vocWords(tb) {
+		words = append(words, []byte(w))
+	}
+	o, err := os.Open("output.txt")
+	if err != nil {
+		panic(err)
+	}
+	defer o.Close()
+	scanner := bufio.NewScanner(o)
+	for scanner.Scan() {
+		stems = append(stems, append([]byte(nil), scanner.Bytes()...))
+	}
+	return words, stems
+}
+
+func TestStemBatch(t *testing.T) {
+	words, expected := vocStems(t)
+
+	fixtures := []BatchOptions{
+		{},
+		{Workers: 1},
+		{Workers: 3, ChunkSize: 7},
+		{Workers: 16, ChunkSize: 1},
+		{Workers: 4, Stemmer: Porter{}},
+		{Workers: 4, Stemmer: NewCachedStemmer(Porter{}, 100)},
+	}
+
+	for _, opts := range fixtures {
+		var reports []BatchStats
+		opts.Progress = func(s BatchStats) { reports = append(reports, s) }
+		stems, stats, err := StemBatch(context.Background(), words, opts)
+		if err != nil {
+			t.Fatalf("StemBatch() returned an error: '%v'", err)
+		}
+		for i := range words {
+			if !bytes.Equal(stems[i], expected[i]) {
+				t.Errorf("StemBatch() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", words[i], stems[i], expected[i])
+			}
+		}
+		if stats.Words != int64(len(words)) {
+			t.Errorf("StemBatch() stats not what was expected, return: '%d' expected: '%d'", stats.Words, len(words))
+		}
+		if len(reports) == 0 || reports[len(reports)-1] != stats {
+			t.Errorf("StemBatch() did not report its stats last, return: '%+v' expected: '%+v'", reports, stats)
+		}
+	}
+}
+
+func TestStemBatchCancel(t *testing.T) {
+	words, _ := vocStems(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stems, stats, err := StemBatch(ctx, words, BatchOptions{Workers: 2})
+	if err != context.Canceled {
+		t.Errorf("StemBatch() error not what was expected, return: '%v' expected: '%v'", err, context.Canceled)
+	}
+	if stats.Words != 0 || stems[0] != nil {
+		t.Errorf("StemBatch() stemmed words after its context was done, return: '%d'", stats.Words)
+	}
+}
+
+func TestStemPipeline(t *testing.T) {
+	words, expected := vocStems(t)
+
+	fixtures := []BatchOptions{
+		{},
+		{Workers: 3, ChunkSize: 5},
+		{Workers: 8, ChunkSize: 1, Stemmer: Porter{}},
+	}
+
+	for _, opts := range fixtures {
+		in := make(chan []byte)
+		go func() {
+			for _, word := range words {
+				in <- word
+			}
+			close(in)
+		}()
+		out, stats := StemPipeline(context.Background(), in, opts)
+		i := 0
+		for stem := range out {
+			if i < len(expected) && !bytes.Equal(stem, expected[i]) {
+				t.Errorf("StemPipeline() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", words[i], stem, expected[i])
+			}
+			i++
+		}
+		if i != len(words) {
+			t.Errorf("StemPipeline() sent %d stems, expected %d", i, len(words))
+		}
+		if s := <-stats; s.Words != int64(len(words)) {
+			t.Errorf("StemPipeline() stats not what was expected, return: '%d' expected: '%d'", s.Words, len(words))
+		}
+	}
+}
+
+func TestStemPipelineCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan []byte)
+	out, stats := StemPipeline(ctx, in, BatchOptions{Workers: 2, ChunkSize: 4})
+	in <- []byte("ponies")
+	if stem := <-out; string(stem) != "poni" {
+		t.Errorf("StemPipeline() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", "ponies", stem, "poni")
+	}
+	// The input stays open: only the context ends the pipeline.
+	cancel()
+	select {
+	case <-stats:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("StemPipeline() did not stop when its context was done")
+	}
+	for range out {
+	}
+}
+
+//
+// The step functions, of the tables and of the generated code, run at the
+// same time on every word of voc.txt; with -race this shows they share no
+// mutable state.
+//
+func TestStepsConcurrent(t *testing.T) {
+	words, expected := vocStems(t)
+	tables := []func([]byte, *trace) []byte{firstA, firstB, firstC, second, third, four, fiveA, fiveB}
+	generated := []func([]byte) []byte{porterStep1a, porterStep1b, porterStep1c, porterStep2, porterStep3, porterStep4, porterStep5a, porterStep5b}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := g; i < len(words); i += 2 {
+				if len(words[i]) < 3 {
+					continue
+				}
+				a := append([]byte(nil), words[i]...)
+				b := append([]byte(nil), words[i]...)
+				for k := range tables {
+					a = tables[k](a, nil)
+					b = generated[k](b)
+				}
+				if !bytes.Equal(a, expected[i]) || !bytes.Equal(b, expected[i]) {
+					t.Errorf("steps return value not what was expected, pass: '%s' return: '%s' and '%s' expected: '%s'", words[i], a, b, expected[i])
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func BenchmarkStemBatch(b *testing.B) {
+	words, _ := vocStems(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		StemBatch(context.Background(), words, BatchOptions{})
+	}
+	b.ReportMetric(float64(b.N*len(words))/b.Elapsed().Seconds(), "words/s")
+}
+
+func BenchmarkStemWordByWord(b *testing.B) {
+	words, _ := vocStems(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, word := range words {
+			Stem(word)
+		}
+	}
+	b.ReportMetric(float64(b.N*len(words))/b.Elapsed().Seconds(), "words/s")
+}
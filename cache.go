@@ -0,0 +1,216 @@
+package stemmer
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+//
+// CachedStemmer wraps a Stemmer with a cache of the stems of the words it
+// stemmed last. Texts use the same few thousand words over and over, so with
+// a cache of a few thousand entries most words are stemmed only once.
+//
+// The cache holds at most the number of words it was made with. It is split
+// into shards by a hash of the word, each behind a lock of its own, so
+// goroutines stemming at the same time rarely wait for each other. A shard
+// evicts with the CLOCK algorithm, an approximation of least recently used:
+// a hit only sets the reference bit of its entry, so it takes the lock of the
+// shard for reading and many goroutines can find stems in the same shard at
+// once. Stem and StemString are safe for concurrent use.
+//
+type CachedStemmer struct {
+	stemmer Stemmer
+	shards  []cacheShard
+	mask    uint32
+}
+
+//
+// CacheStats are the counters of a CachedStemmer.
+//
+type CacheStats struct {
+	// Hits and Misses count the words found and not found in the cache,
+	// Entries the words it holds.
+	Hits    uint64
+	Misses  uint64
+	Entries int
+}
+
+//
+// HitRate returns the share of the words found in the cache, or 0 if there
+// were none.
+//
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// maxCacheShards is the most shards a cache is split into.
+const maxCacheShards = 64
+
+//
+// NewCachedStemmer returns a CachedStemmer wrapping s with a cache of size
+// words. With a size of 0 or less nothing is cached.
+//
+func NewCachedStemmer(s Stemmer, size int) *CachedStemmer {
+	n := 1
+	for n < maxCacheShards && n*2 <= size/16 {
+		n *= 2
+	}
+	c := &CachedStemmer{stemmer: s, shards: make([]cacheShard, n), mask: uint32(n - 1)}
+	for i := range c.shards {
+		// The first shards take the remainder, so the sizes add up to size.
+		c.shards[i].size = size / n
+		if i < size%n {
+			c.shards[i].size++
+		}
+		c.shards[i].entries = make(map[string]int, c.shards[i].size)
+	}
+	return c
+}
+
+//
+// Stem returns the stem of word from the cache, or from the wrapped stemmer
+// if it is not there yet. The stem is a new slice.
+//
+func (c *CachedStemmer) Stem(word []byte) []byte {
+	shard := &c.shards[hashBytes(word)&c.mask]
+	if stem, ok := shard.get(string(word)); ok {
+		return []byte(stem)
+	}
+	stem := c.stemmer.Stem(word)
+	shard.put(string(word), string(stem))
+	return stem
+}
+
+//
+// StemString is Stem for strings. It does not allocate when the word is in
+// the cache.
+//
+func (c *CachedStemmer) StemString(word string) string {
+	shard := &c.shards[hashString(word)&c.mask]
+	if stem, ok := shard.get(word); ok {
+		return stem
+	}
+	stem := c.stemmer.StemString(word)
+	shard.put(word, stem)
+	return stem
+}
+
+//
+// Name returns the name of the wrapped stemmer.
+//
+func (c *CachedStemmer) Name() string {
+	return c.stemmer.Name()
+}
+
+//
+// Stats returns the counters of c.
+//
+func (c *CachedStemmer) Stats() CacheStats {
+	var stats CacheStats
+	for i := range c.shards {
+		shard := &c.shards[i]
+		stats.Hits += atomic.LoadUint64(&shard.hits)
+		stats.Misses += atomic.LoadUint64(&shard.misses)
+		shard.mu.RLock()
+		stats.Entries += len(shard.entries)
+		shard.mu.RUnlock()
+	}
+	return stats
+}
+
+//
+// cacheShard is a CLOCK cache of stems: slots is a ring the hand goes round
+// when the shard is full, clearing the reference bits it finds set and
+// evicting the first entry whose bit is clear, that is the first one not
+// found since the hand last passed.
+//
+type cacheShard struct {
+	// The counters come first to keep them 64-bit aligned. Each shard has
+	// its own, so goroutines do not all write the same ones.
+	hits   uint64
+	misses uint64
+
+	mu      sync.RWMutex
+	size    int
+	entries map[string]int
+	slots   []cacheSlot
+	hand    int
+	// The padding keeps the locks and counters of neighbouring shards out
+	// of the same cache line.
+	_ [64]byte
+}
+
+type cacheSlot struct {
+	word, stem string
+	// referenced is set by the hits, under the read lock, and cleared by
+	// the hand.
+	referenced uint32
+}
+
+func (s *cacheShard) get(word string) (string, bool) {
+	s.mu.RLock()
+	i, ok := s.entries[word]
+	if !ok {
+		s.mu.RUnlock()
+		atomic.AddUint64(&s.misses, 1)
+		return "", false
+	}
+	slot := &s.slots[i]
+	stem := slot.stem
+	// Checking first saves writing the cache line on most hits.
+	if atomic.LoadUint32(&slot.referenced) == 0 {
+		atomic.StoreUint32(&slot.referenced, 1)
+	}
+	s.mu.RUnlock()
+	atomic.AddUint64(&s.hits, 1)
+	return stem, true
+}
+
+func (s *cacheShard) put(word, stem string) {
+	if s.size <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i, ok := s.entries[word]; ok {
+		// Another goroutine stemmed the word meanwhile.
+		s.slots[i].stem = stem
+		return
+	}
+	if len(s.slots) < s.size {
+		s.entries[word] = len(s.slots)
+		s.slots = append(s.slots, cacheSlot{word: word, stem: stem})
+		return
+	}
+	for s.slots[s.hand].referenced != 0 {
+		s.slots[s.hand].referenced = 0
+		s.hand = (s.hand + 1) % len(s.slots)
+	}
+	slot := &s.slots[s.hand]
+	delete(s.entries, slot.word)
+	slot.word, slot.stem = word, stem
+	s.entries[word] = s.hand
+	s.hand = (s.hand + 1) % len(s.slots)
+}
+
+//
+// hashBytes and hashString return the 32-bit FNV-1a hash of a word.
+//
+func hashBytes(b []byte) uint32 {
+	h := uint32(2166136261)
+	for _, c := range b {
+		h = (h ^ uint32(c)) * 16777619
+	}
+	return h
+}
+
+func hashString(s string) uint32 {
+	h := uint32(2166136261)
+	for i := 0; i < len(s); i++ {
+		h = (h ^ uint32(s[i])) * 16777619
+	}
+	return h
+}
@@ -0,0 +1,166 @@
+package stemmer
+
+import (
+	"bufio"
+	"math/rand"
+	"os"
+	"sync"
+	"testing"
+)
+
+func TestCachedStemmer(t *testing.T) {
+	c := NewCachedStemmer(Porter{}, 100)
+
+	fixtures := []string{
+		"caresses",
+		"ponies",
+		"caresses",
+		"Ponies",
+		"ponies",
+	}
+
+	stemmed := []string{
+		"caress",
+		"poni",
+		"caress",
+		"poni",
+		"poni",
+	}
+
+	for k, value := range fixtures {
+		if result := string(c.Stem([]byte(value))); result != stemmed[k] {
+			t.Errorf("CachedStemmer.Stem() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, stemmed[k])
+		}
+		if result := c.StemString(value); result != stemmed[k] {
+			t.Errorf("CachedStemmer.StemString() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, stemmed[k])
+		}
+	}
+
+	expected := CacheStats{Hits: 7, Misses: 3, Entries: 3}
+	if stats := c.Stats(); stats != expected {
+		t.Errorf("CachedStemmer.Stats() return value not what was expected, return: '%+v' expected: '%+v'", stats, expected)
+	}
+	if rate := c.Stats().HitRate(); rate != 0.7 {
+		t.Errorf("CacheStats.HitRate() return value not what was expected, return: '%v' expected: '%v'", rate, 0.7)
+	}
+	if name := c.Name(); name != "porter" {
+		t.Errorf("CachedStemmer.Name() return value not what was expected, return: '%s' expected: '%s'", name, "porter")
+	}
+}
+
+func TestCachedStemmerEviction(t *testing.T) {
+	c := NewCachedStemmer(Porter{}, 3)
+	for _, word := range []string{"ponies", "caresses", "ties", "ponies", "cats", "caresses"} {
+		c.StemString(word)
+	}
+
+	// The second ponies set its reference bit, so the hand passed it and
+	// caresses went when cats came, then ties when caresses came back.
+	expected := CacheStats{Hits: 1, Misses: 5, Entries: 3}
+	if stats := c.Stats(); stats != expected {
+		t.Errorf("CachedStemmer.Stats() return value not what was expected, return: '%+v' expected: '%+v'", stats, expected)
+	}
+
+	for _, size := range []int{0, 1, 15, 16, 1000, 5003} {
+		c := NewCachedStemmer(Porter{}, size)
+		for _, word := range vocWords(t)[:2*size+10] {
+			c.StemString(word)
+		}
+		if entries := c.Stats().Entries; entries != size {
+			t.Errorf("CachedStemmer.Stats() entries not what was expected, pass: '%d' return: '%d' expected: '%d'", size, entries, size)
+		}
+	}
+}
+
+func TestCachedStemmerAllocs(t *testing.T) {
+	c := NewCachedStemmer(Porter{}, 100)
+	c.StemString("troubles")
+	if allocs := testing.AllocsPerRun(100, func() { c.StemString("troubles") }); allocs != 0 {
+		t.Errorf("CachedStemmer.StemString() allocated, pass: '%s' allocs: '%v' expected: '%v'", "troubles", allocs, 0)
+	}
+}
+
+//
+// The stems of a cache smaller than the vocabulary, used from several
+// goroutines, must be those of Stem; run with -race.
+//
+func TestCachedStemmerConcurrent(t *testing.T) {
+	words := vocWords(t)
+	c := NewCachedStemmer(Porter{}, 1000)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := g; i < len(words); i += 3 {
+				if result, expected := c.StemString(words[i]), StemString(words[i]); result != expected {
+					t.Errorf("CachedStemmer.StemString() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", words[i], result, expected)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+var (
+	vocOnce sync.Once
+	voc     []string
+)
+
+func vocWords(tb testing.TB) []string {
+	vocOnce.Do(func() {
+		f, err := os.Open("voc.txt")
+		if err != nil {
+			panic(err)
+		}
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			voc = append(voc, scanner.Text())
+		}
+	})
+	return voc
+}
+
+//
+// zipfWords returns a text of n words of voc.txt drawn with Zipf's law, as
+// words are used in real texts.
+//
+func zipfWords(tb testing.TB, n int) []string {
+	words := vocWords(tb)
+	r := rand.New(rand.NewSource(1))
+	zipf := rand.NewZipf(r, 1.1, 1, uint64(len(words)-1))
+	text := make([]string, n)
+	for i := range text {
+		text[i] = words[zipf.Uint64()]
+	}
+	return text
+}
+
+func benchmarkParallel(b *testing.B, s Stemmer) {
+	text := zipfWords(b, 1<<16)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := rand.Intn(len(text))
+		for pb.Next() {
+			s.StemString(text[i%len(text)])
+			i++
+		}
+	})
+	if c, ok := s.(*CachedStemmer); ok {
+		b.ReportMetric(100*c.Stats().HitRate(), "%hits")
+	}
+}
+
+func BenchmarkStemParallel(b *testing.B) {
+	benchmarkParallel(b, Porter{})
+}
+
+func BenchmarkCachedStemmerParallel(b *testing.B) {
+	benchmarkParallel(b, NewCachedStemmer(Porter{}, 4096))
+}
+
+func BenchmarkCachedStemmerParallelAll(b *testing.B) {
+	benchmarkParallel(b, NewCachedStemmer(Porter{}, len(vocWords(b))))
+}
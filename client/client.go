@@ -0,0 +1,159 @@
+//
+// Package client calls the Stemmer service of proto/stemmer.proto, served by
+// cmd/stemrpc:
+//
+//    c, err := client.Dial("localhost:9090", grpc.WithTransportCredentials(insecure.NewCredentials()))
+//    if err != nil {
+//      log.Fatal(err)
+//    }
+//    defer c.Close()
+//    stem, err := c.Stem(ctx, "running") // run
+//
+package client
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/pigi72333/stemmer/analysis"
+	"github.com/pigi72333/stemmer/proto/stemmerpb"
+	"github.com/pigi72333/stemmer/tokenizer"
+)
+
+//
+// Client is a client of the Stemmer service. It can be used by several
+// goroutines.
+//
+type Client struct {
+	// Algorithm is the name of the stemmer Stem and StemBatch ask for; the
+	// server uses its default if it is empty.
+	Algorithm string
+
+	conn *grpc.ClientConn
+	rpc  stemmerpb.StemmerClient
+}
+
+//
+// Dial returns a Client of the service at target, see grpc.NewClient. The
+// options must set the transport credentials, e.g.
+// grpc.WithTransportCredentials(insecure.NewCredentials()) for a server
+// without TLS.
+//
+func Dial(target string, opts ...grpc.DialOption) (*Client, error) {
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c := New(conn)
+	c.conn = conn
+	return c, nil
+}
+
+//
+// New returns a Client calling the service over cc, which it does not close.
+//
+func New(cc grpc.ClientConnInterface) *Client {
+	return &Client{rpc: stemmerpb.NewStemmerClient(cc)}
+}
+
+//
+// Close closes the connection of a Client returned by Dial.
+//
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+//
+// Stem returns the stem of word.
+//
+func (c *Client) Stem(ctx context.Context, word string) (string, error) {
+	resp, err := c.rpc.Stem(ctx, &stemmerpb.StemRequest{Word: word, Algorithm: c.Algorithm})
+	if err != nil {
+		return "", err
+	}
+	return resp.GetStem(), nil
+}
+
+//
+// StemBatch returns the stems of words, in their order.
+//
+func (c *Client) StemBatch(ctx context.Context, words []string) ([]string, error) {
+	resp, err := c.rpc.StemBatch(ctx, &stemmerpb.StemBatchRequest{Words: words, Algorithm: c.Algorithm})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetStems(), nil
+}
+
+//
+// Analyze opens a stream of texts to analyze, which lasts until ctx is done
+// or the texts are all sent and their tokens received.
+//
+func (c *Client) Analyze(ctx context.Context) (*AnalyzeStream, error) {
+	stream, err := c.rpc.Analyze(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &AnalyzeStream{stream: stream}, nil
+}
+
+//
+// AnalyzeStream sends texts to the server and receives their tokens, in the
+// order of the texts. Send and Recv may be called from two goroutines, one
+// each, so texts can be sent while the tokens of earlier ones arrive.
+//
+type AnalyzeStream struct {
+	stream stemmerpb.Stemmer_AnalyzeClient
+}
+
+//
+// Send sends a text.
+//
+func (s *AnalyzeStream) Send(text string) error {
+	return s.stream.Send(&stemmerpb.AnalyzeRequest{Text: text})
+}
+
+//
+// CloseSend tells the server no more texts follow. Recv returns io.EOF once
+// the tokens of all the texts are received.
+//
+func (s *AnalyzeStream) CloseSend() error {
+	return s.stream.CloseSend()
+}
+
+//
+// Recv returns the tokens of the next text.
+//
+func (s *AnalyzeStream) Recv() ([]analysis.Token, error) {
+	resp, err := s.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	tokens := make([]analysis.Token, len(resp.GetTokens()))
+	for i, tok := range resp.GetTokens() {
+		tokens[i] = analysis.Token{
+			Term:              tok.GetTerm(),
+			Start:             int(tok.GetStart()),
+			End:               int(tok.GetEnd()),
+			PositionIncrement: int(tok.GetPositionIncrement()),
+			Type:              tokenType(tok.GetType()),
+		}
+	}
+	return tokens, nil
+}
+
+//
+// tokenType returns the tokenizer.Type called name, or Word if there is none.
+//
+func tokenType(name string) tokenizer.Type {
+	for t := tokenizer.Word; t <= tokenizer.Hashtag; t++ {
+		if t.String() == name {
+			return t
+		}
+	}
+	return tokenizer.Word
+}
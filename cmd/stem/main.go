@@ -0,0 +1,255 @@
+//
+// Command stem prints the stems of the words given as arguments or, without
+// arguments, of the lines of the standard input:
+//
+//    stem running ponies
+//    stem -algorithm porter2 -format tsv < words.txt
+//    stem -preserve < article.txt
+//
+// Every line is split into the words of package tokenizer, and each word is
+// stemmed; with -mode line a line is stemmed as a whole, one stem per line.
+// The stems are written one per line (-format plain), after their word and a
+// tab (-format tsv) or as JSON objects, one per line (-format json). -trace
+// adds the steps that changed the word, for the algorithms that report them,
+// and -preserve writes the text back with its words replaced by their stems.
+//
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pigi72333/stemmer"
+	"github.com/pigi72333/stemmer/tokenizer"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+//
+// tracer is a stemmer that can report the steps of a stem, like
+// stemmer.Porter and stemmer.RuleStemmer.
+//
+type tracer interface {
+	StemTrace(word []byte) (stem []byte, steps []stemmer.StepResult)
+}
+
+//
+// run runs the command with args and returns its exit status.
+//
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("stem", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	algorithm := flags.String("algorithm", "porter", "stem with the algorithm registered as `name`")
+	mode := flags.String("mode", "word", "stem every `word` of a line, or every line as a whole")
+	format := flags.String("format", "plain", "write the stems as `plain` text, tsv or json")
+	trace := flags.Bool("trace", false, "show the steps that changed each word")
+	preserve := flags.Bool("preserve", false, "write the text back with its words stemmed")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: stem [flags] [word ...]\n")
+		flags.PrintDefaults()
+		fmt.Fprintf(stderr, "algorithms: %s\n", strings.Join(stemmer.Names(), ", "))
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	p := &printer{trace: *trace, format: *format}
+	var ok bool
+	if p.stemmer, ok = stemmer.Lookup(*algorithm); !ok {
+		return usageError(stderr, "unknown algorithm %q, have %s", *algorithm, strings.Join(stemmer.Names(), ", "))
+	}
+	switch *mode {
+	case "word":
+	case "line":
+		p.lines = true
+	default:
+		return usageError(stderr, "unknown mode %q, have word and line", *mode)
+	}
+	switch *format {
+	case "plain", "tsv", "json":
+	default:
+		return usageError(stderr, "unknown format %q, have plain, tsv and json", *format)
+	}
+	if p.trace {
+		if p.tracer, ok = p.stemmer.(tracer); !ok {
+			return usageError(stderr, "%s cannot trace its stems", *algorithm)
+		}
+	}
+	if *preserve {
+		if p.lines || p.trace || p.format != "plain" {
+			return usageError(stderr, "-preserve writes plain text and cannot be used with -mode line, -trace or -format")
+		}
+		p.preserve = true
+	}
+
+	out := bufio.NewWriter(stdout)
+	p.out = out
+	if p.format == "json" {
+		p.enc = json.NewEncoder(out)
+		p.enc.SetEscapeHTML(false)
+	}
+	var err error
+	if flags.NArg() > 0 {
+		for _, arg := range flags.Args() {
+			if p.preserve {
+				arg += "\n"
+			}
+			if err = p.line(arg); err != nil {
+				break
+			}
+		}
+	} else {
+		err = p.read(stdin)
+	}
+	if ferr := out.Flush(); err == nil {
+		err = ferr
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "stem: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func usageError(stderr io.Writer, format string, args ...interface{}) int {
+	fmt.Fprintf(stderr, "stem: "+format+"\n", args...)
+	return 2
+}
+
+type printer struct {
+	stemmer  stemmer.Stemmer
+	tracer   tracer
+	lines    bool
+	format   string
+	trace    bool
+	preserve bool
+
+	out *bufio.Writer
+	enc *json.Encoder
+}
+
+//
+// read stems the lines of r. The lines keep their line ends for -preserve.
+//
+func (p *printer) read(r io.Reader) error {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			if !p.preserve {
+				line = strings.TrimRight(line, "\r\n")
+			}
+			if perr := p.line(line); perr != nil {
+				return perr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (p *printer) line(line string) error {
+	if p.lines {
+		return p.word(strings.TrimSpace(line))
+	}
+	t := tokenizer.New(line)
+	end := 0
+	for tok, ok := t.Next(); ok; tok, ok = t.Next() {
+		if p.preserve {
+			p.out.WriteString(line[end:tok.Start])
+			if tok.Type == tokenizer.Word {
+				p.out.WriteString(p.stemmer.StemString(tok.Text))
+			} else {
+				p.out.WriteString(tok.Text)
+			}
+			end = tok.End
+			continue
+		}
+		if tok.Type != tokenizer.Word {
+			continue
+		}
+		if err := p.word(tok.Text); err != nil {
+			return err
+		}
+	}
+	if p.preserve {
+		_, err := p.out.WriteString(line[end:])
+		return err
+	}
+	return nil
+}
+
+//
+// record is the JSON object written for a word.
+//
+type record struct {
+	Word  string `json:"word"`
+	Stem  string `json:"stem"`
+	Steps []step `json:"steps,omitempty"`
+}
+
+type step struct {
+	Step      string `json:"step"`
+	Suffix    string `json:"suffix"`
+	Measure   int    `json:"measure"`
+	Condition string `json:"condition,omitempty"`
+	Before    string `json:"before"`
+	After     string `json:"after"`
+}
+
+func (p *printer) word(word string) error {
+	var stem string
+	var steps []stemmer.StepResult
+	if p.trace {
+		s, all := p.tracer.StemTrace([]byte(word))
+		stem = string(s)
+		// Only the steps that changed the word are shown; the others matched
+		// a suffix but not the condition.
+		for _, r := range all {
+			if string(r.Before) != string(r.After) {
+				steps = append(steps, r)
+			}
+		}
+	} else {
+		stem = p.stemmer.StemString(word)
+	}
+
+	switch p.format {
+	case "json":
+		rec := record{Word: word, Stem: stem}
+		for _, r := range steps {
+			rec.Steps = append(rec.Steps, step{r.Step, r.Suffix, r.Measure, r.Condition, string(r.Before), string(r.After)})
+		}
+		return p.enc.Encode(rec)
+	case "tsv":
+		p.out.WriteString(word)
+		p.out.WriteByte('\t')
+		p.out.WriteString(stem)
+		if p.trace {
+			p.out.WriteByte('\t')
+			for i, r := range steps {
+				if i > 0 {
+					p.out.WriteByte(' ')
+				}
+				fmt.Fprintf(p.out, "%s:%s->%s", r.Step, r.Before, r.After)
+			}
+		}
+	default:
+		p.out.WriteString(stem)
+		for _, r := range steps {
+			fmt.Fprintf(p.out, "\n  %s %q m=%d %s: %s -> %s", r.Step, r.Suffix, r.Measure, r.Condition, r.Before, r.After)
+		}
+	}
+	return p.out.WriteByte('\n')
+}
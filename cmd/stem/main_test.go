@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	fixtures := [][]string{
+		{"caresses", "ponies"},
+		{"-mode", "line", "The ponies"},
+		{"-format", "tsv", "Ponies running."},
+		{"-format", "json", "-algorithm", "s", "ponies"},
+		{"-preserve", "Ponies, running  to http://example.com!"},
+		{"-trace", "conflated"},
+		{"-format", "tsv", "-trace", "hopping"},
+		{"-format", "json", "-trace", "tree"},
+	}
+
+	outputs := []string{
+		"caress\nponi\n",
+		"the poni\n",
+		"Ponies\tponi\nrunning\trun\n",
+		"{\"word\":\"ponies\",\"stem\":\"pony\"}\n",
+		"poni, run  to http://example.com!\n",
+		"conflat\n" +
+			"  1b \"ed\" m=2 *v*: conflated -> conflat\n" +
+			"  1b2 \"at\" m=1 : conflat -> conflate\n" +
+			"  5a \"e\" m=2 m>1 or (m=1 and not *o): conflate -> conflat\n",
+		"hopping\thop\t1b:hopping->hopp 1b2:hopp->hop\n",
+		"{\"word\":\"tree\",\"stem\":\"tree\"}\n",
+	}
+
+	for k, args := range fixtures {
+		var stdout, stderr bytes.Buffer
+		if status := run(args, strings.NewReader(""), &stdout, &stderr); status != 0 {
+			t.Fatalf("run() returned %d, pass: '%v' stderr: '%s'", status, args, stderr.String())
+		}
+		if result := stdout.String(); result != outputs[k] {
+			t.Errorf("run() return value not what was expected, pass: '%v' return: '%s' expected: '%s'", args, result, outputs[k])
+		}
+	}
+}
+
+func TestRunStdin(t *testing.T) {
+	fixtures := []string{
+		"caresses\r\nponies\n\nties",
+		"Ponies,\trunning!\r\n\nA tree.",
+	}
+
+	args := [][]string{
+		{"-mode", "line"},
+		{"-preserve"},
+	}
+
+	outputs := []string{
+		"caress\nponi\n\nti\n",
+		"poni,\trun!\r\n\na tree.",
+	}
+
+	for k, value := range fixtures {
+		var stdout, stderr bytes.Buffer
+		if status := run(args[k], strings.NewReader(value), &stdout, &stderr); status != 0 {
+			t.Fatalf("run() returned %d, pass: '%s' stderr: '%s'", status, value, stderr.String())
+		}
+		if result := stdout.String(); result != outputs[k] {
+			t.Errorf("run() return value not what was expected, pass: '%q' return: '%q' expected: '%q'", value, result, outputs[k])
+		}
+	}
+}
+
+func TestRunError(t *testing.T) {
+	fixtures := [][]string{
+		{"-algorithm", "klingon", "word"},
+		{"-mode", "sentence", "word"},
+		{"-format", "xml", "word"},
+		{"-trace", "-algorithm", "lovins", "word"},
+		{"-preserve", "-format", "json", "word"},
+		{"-unknown"},
+	}
+
+	for _, args := range fixtures {
+		var stdout, stderr bytes.Buffer
+		if status := run(args, strings.NewReader(""), &stdout, &stderr); status != 2 {
+			t.Errorf("run() did not fail, pass: '%v' return: %d", args, status)
+		}
+	}
+}
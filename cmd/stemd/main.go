@@ -0,0 +1,82 @@
+//
+// Command stemd serves the stemmers of package stemmer over HTTP, so programs
+// that are not written in Go get the same stems:
+//
+//    POST /stem          a JSON array of words (Content-Type: application/json)
+//                        gets the array of their stems; any other body is
+//                        text and gets the array of its words, with their
+//                        stems and byte offsets
+//    GET  /stem/{word}   gets the word and its stem
+//    GET  /metrics       the words stemmed and the use of the cache, in the
+//                        text format of Prometheus
+//
+// Both /stem requests take the stemmer from the algorithm parameter, e.g.
+// /stem/running?algorithm=porter2, and use -algorithm without one. Each
+// algorithm requested keeps the stems of up to -cache words in a cache of its
+// own, so with k algorithms in use up to k times -cache stems are kept.
+// Bodies larger than -max-body bytes or with more than -max-words words are
+// refused. On SIGINT or SIGTERM stemd stops accepting connections and waits
+// for the requests in progress, up to -shutdown-timeout.
+//
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pigi72333/stemmer"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "listen on `address`")
+	algorithm := flag.String("algorithm", "porter", "stem with the algorithm registered as `name` by default")
+	maxBody := flag.Int64("max-body", 1<<20, "refuse bodies larger than `n` bytes")
+	maxWords := flag.Int("max-words", 10000, "refuse bodies with more than `n` words")
+	cacheSize := flag.Int("cache", 100000, "cache up to `n` stems per algorithm in use")
+	timeout := flag.Duration("shutdown-timeout", 10*time.Second, "wait up to `duration` for the requests in progress on shutdown")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: stemd [flags]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if _, ok := stemmer.Lookup(*algorithm); !ok {
+		fmt.Fprintf(os.Stderr, "stemd: unknown algorithm %q\n", *algorithm)
+		os.Exit(2)
+	}
+
+	srv := &http.Server{
+		Addr:         *addr,
+		Handler:      newServer(*algorithm, *maxBody, *maxWords, *cacheSize).handler(),
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+	done := make(chan struct{})
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		<-sig
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Printf("stemd: shutdown: %v", err)
+		}
+		close(done)
+	}()
+
+	log.Printf("stemd: listening on %s", *addr)
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatalf("stemd: %v", err)
+	}
+	<-done
+}
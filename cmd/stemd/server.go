@@ -0,0 +1,259 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pigi72333/stemmer"
+	"github.com/pigi72333/stemmer/tokenizer"
+)
+
+//
+// server answers the requests of stemd.
+//
+type server struct {
+	// algorithm is the stemmer used when a request does not name one.
+	algorithm string
+	// maxBody is the largest body of a POST, in bytes, and maxWords the
+	// most words it may hold.
+	maxBody  int64
+	maxWords int
+
+	// cached holds the stemmers requested so far, by name, each with a
+	// cache of cacheSize stems: the size is per algorithm, not shared.
+	cacheSize int
+	mu        sync.Mutex
+	cached    map[string]*stemmer.CachedStemmer
+
+	words *meter
+	now   func() time.Time
+}
+
+func newServer(algorithm string, maxBody int64, maxWords, cacheSize int) *server {
+	return &server{
+		algorithm: algorithm,
+		maxBody:   maxBody,
+		maxWords:  maxWords,
+		cacheSize: cacheSize,
+		cached:    make(map[string]*stemmer.CachedStemmer),
+		words:     &meter{},
+		now:       time.Now,
+	}
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stem", s.stemBatch)
+	mux.HandleFunc("/stem/", s.stemWord)
+	mux.HandleFunc("/metrics", s.metrics)
+	return mux
+}
+
+//
+// token is a word of a text posted to /stem, with its byte offsets.
+//
+type token struct {
+	Word  string `json:"word"`
+	Stem  string `json:"stem"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+//
+// stemBatch answers POST /stem. A JSON array of words gets the array of
+// their stems; any other body is text, split into words by package
+// tokenizer, and gets an array of tokens.
+//
+func (s *server) stemBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		httpError(w, http.StatusMethodNotAllowed, "use POST")
+		return
+	}
+	st, ok := s.stemmer(w, r)
+	if !ok {
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, s.maxBody))
+	if err != nil {
+		httpError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("the body is larger than %d bytes", s.maxBody))
+		return
+	}
+
+	ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if ct == "application/json" {
+		var words []string
+		if err := json.Unmarshal(body, &words); err != nil {
+			httpError(w, http.StatusBadRequest, "the body is not a JSON array of strings: "+err.Error())
+			return
+		}
+		if len(words) > s.maxWords {
+			httpError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("more than %d words", s.maxWords))
+			return
+		}
+		stems := make([]string, len(words))
+		for i, word := range words {
+			stems[i] = st.StemString(word)
+		}
+		s.words.add(s.now(), len(words))
+		writeJSON(w, stems)
+		return
+	}
+
+	text := string(body)
+	tokens := []token{}
+	t := tokenizer.New(text)
+	for tok, ok := t.Next(); ok; tok, ok = t.Next() {
+		if tok.Type != tokenizer.Word {
+			continue
+		}
+		if len(tokens) == s.maxWords {
+			httpError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("more than %d words", s.maxWords))
+			return
+		}
+		tokens = append(tokens, token{tok.Text, st.StemString(tok.Text), tok.Start, tok.End})
+	}
+	s.words.add(s.now(), len(tokens))
+	writeJSON(w, tokens)
+}
+
+//
+// stemWord answers GET /stem/{word}.
+//
+func (s *server) stemWord(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		httpError(w, http.StatusMethodNotAllowed, "use GET")
+		return
+	}
+	word := strings.TrimPrefix(r.URL.Path, "/stem/")
+	if word == "" || strings.Contains(word, "/") {
+		httpError(w, http.StatusNotFound, "no word in "+r.URL.Path)
+		return
+	}
+	st, ok := s.stemmer(w, r)
+	if !ok {
+		return
+	}
+	stem := st.StemString(word)
+	s.words.add(s.now(), 1)
+	writeJSON(w, token{Word: word, Stem: stem, End: len(word)})
+}
+
+//
+// stemmer returns the cached stemmer named by the algorithm parameter of r,
+// or the default one. It answers the request itself if there is no such
+// stemmer.
+//
+func (s *server) stemmer(w http.ResponseWriter, r *http.Request) (stemmer.Stemmer, bool) {
+	name := r.URL.Query().Get("algorithm")
+	if name == "" {
+		name = s.algorithm
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.cached[name]; ok {
+		return c, true
+	}
+	st, ok := stemmer.Lookup(name)
+	if !ok {
+		httpError(w, http.StatusBadRequest, fmt.Sprintf("unknown algorithm %q, have %s", name, strings.Join(stemmer.Names(), ", ")))
+		return nil, false
+	}
+	c := stemmer.NewCachedStemmer(st, s.cacheSize)
+	s.cached[name] = c
+	return c, true
+}
+
+//
+// cacheStats returns the counters of the caches of all the stemmers.
+//
+func (s *server) cacheStats() stemmer.CacheStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var total stemmer.CacheStats
+	for _, c := range s.cached {
+		stats := c.Stats()
+		total.Hits += stats.Hits
+		total.Misses += stats.Misses
+		total.Entries += stats.Entries
+	}
+	return total
+}
+
+//
+// metrics answers GET /metrics in the text format of Prometheus.
+//
+func (s *server) metrics(w http.ResponseWriter, r *http.Request) {
+	total, rate := s.words.read(s.now())
+	cache := s.cacheStats()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprintf(w, "# HELP stemd_words_total Words stemmed.\n# TYPE stemd_words_total counter\nstemd_words_total %d\n", total)
+	fmt.Fprintf(w, "# HELP stemd_words_per_second Words stemmed per second over the last minute.\n# TYPE stemd_words_per_second gauge\nstemd_words_per_second %g\n", rate)
+	fmt.Fprintf(w, "# HELP stemd_cache_hits_total Stems found in the cache.\n# TYPE stemd_cache_hits_total counter\nstemd_cache_hits_total %d\n", cache.Hits)
+	fmt.Fprintf(w, "# HELP stemd_cache_misses_total Stems not found in the cache.\n# TYPE stemd_cache_misses_total counter\nstemd_cache_misses_total %d\n", cache.Misses)
+	fmt.Fprintf(w, "# HELP stemd_cache_hit_ratio Share of the stems found in the cache.\n# TYPE stemd_cache_hit_ratio gauge\nstemd_cache_hit_ratio %g\n", cache.HitRate())
+	fmt.Fprintf(w, "# HELP stemd_cache_entries Stems in the cache.\n# TYPE stemd_cache_entries gauge\nstemd_cache_entries %d\n", cache.Entries)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+}
+
+func httpError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{msg})
+}
+
+//
+// meter counts words, in buckets of a second over the last minute for the
+// rate.
+//
+type meter struct {
+	mu      sync.Mutex
+	total   int64
+	counts  [60]int64
+	seconds [60]int64
+}
+
+func (m *meter) add(t time.Time, n int) {
+	sec := t.Unix()
+	i := sec % int64(len(m.counts))
+	m.mu.Lock()
+	if m.seconds[i] != sec {
+		m.seconds[i], m.counts[i] = sec, 0
+	}
+	m.counts[i] += int64(n)
+	m.total += int64(n)
+	m.mu.Unlock()
+}
+
+//
+// read returns the words counted and their rate over the minute before the
+// current second, which is not over yet.
+//
+func (m *meter) read(t time.Time) (total int64, rate float64) {
+	now := t.Unix()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n int64
+	for i, sec := range m.seconds {
+		if sec < now && sec >= now-int64(len(m.counts)) {
+			n += m.counts[i]
+		}
+	}
+	return m.total, float64(n) / float64(len(m.counts))
+}
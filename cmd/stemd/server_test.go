@@ -0,0 +1,88 @@
+package main
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServer(t *testing.T) {
+	fixtures := []struct {
+		method, target, contentType, body string
+	}{
+		{"POST", "/stem", "application/json", `["caresses", "ponies", "tree"]`},
+		{"POST", "/stem?algorithm=porter2", "application/json; charset=utf-8", `["generously"]`},
+		{"POST", "/stem", "text/plain", "Ponies, running!"},
+		{"POST", "/stem", "", ""},
+		{"GET", "/stem/conflated", "", ""},
+		{"GET", "/stem/ponies?algorithm=s", "", ""},
+		{"POST", "/stem", "application/json", `["a", "b", "c", "d"]`},
+		{"POST", "/stem", "text/plain", "a b c d"},
+		{"POST", "/stem", "text/plain", strings.Repeat("a", 101)},
+		{"POST", "/stem", "application/json", `{"word": "ponies"}`},
+		{"POST", "/stem?algorithm=klingon", "application/json", `["ponies"]`},
+		{"GET", "/stem", "", ""},
+		{"DELETE", "/stem/ponies", "", ""},
+		{"GET", "/stem/", "", ""},
+	}
+
+	codes := []int{200, 200, 200, 200, 200, 200, 413, 413, 413, 400, 400, 405, 405, 404}
+
+	outputs := []string{
+		`["caress","poni","tree"]`,
+		`["generous"]`,
+		`[{"word":"Ponies","stem":"poni","start":0,"end":6},{"word":"running","stem":"run","start":8,"end":15}]`,
+		`[]`,
+		`{"word":"conflated","stem":"conflat","start":0,"end":9}`,
+		`{"word":"ponies","stem":"pony","start":0,"end":6}`,
+	}
+
+	s := newServer("porter", 100, 3, 10)
+	h := s.handler()
+	for k, value := range fixtures {
+		r := httptest.NewRequest(value.method, value.target, strings.NewReader(value.body))
+		if value.contentType != "" {
+			r.Header.Set("Content-Type", value.contentType)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != codes[k] {
+			t.Errorf("ServeHTTP() status not what was expected, pass: '%s %s' return: %d expected: %d", value.method, value.target, w.Code, codes[k])
+		}
+		if k < len(outputs) {
+			if result := strings.TrimSpace(w.Body.String()); result != outputs[k] {
+				t.Errorf("ServeHTTP() return value not what was expected, pass: '%s %s' return: '%s' expected: '%s'", value.method, value.target, result, outputs[k])
+			}
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := newServer("porter", 1<<10, 100, 10)
+	s.now = func() time.Time { return now }
+	h := s.handler()
+	for _, body := range []string{`["ponies", "ponies", "tree"]`, `["ponies"]`} {
+		r := httptest.NewRequest("POST", "/stem", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		h.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	now = now.Add(time.Second)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	expected := []string{
+		"stemd_words_total 4\n",
+		"stemd_words_per_second 0.0666",
+		"stemd_cache_hits_total 2\n",
+		"stemd_cache_misses_total 2\n",
+		"stemd_cache_hit_ratio 0.5\n",
+		"stemd_cache_entries 2\n",
+	}
+	for _, value := range expected {
+		if !strings.Contains(w.Body.String(), value) {
+			t.Errorf("metrics() return value not what was expected, return: '%s' expected: '%s'", w.Body.String(), value)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+//
+// Command stemgen compiles a rule file into Go code for package stemmer, see
+// stemmer.GenerateGo. It is run by go generate:
+//
+//    stemgen -o porter_gen.go rules/porter.json
+//
+// Without -o the code is written to the standard output.
+//
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pigi72333/stemmer"
+)
+
+func main() {
+	out := flag.String("o", "", "write the code to `file`")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: stemgen [-o file] rules.json\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := generate(flag.Arg(0), *out); err != nil {
+		fmt.Fprintf(os.Stderr, "stemgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func generate(path, out string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	rs, err := stemmer.ParseRules(path, data)
+	if err != nil {
+		return err
+	}
+	var code bytes.Buffer
+	if err := stemmer.GenerateGo(&code, rs, path); err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(code.Bytes())
+		return err
+	}
+	return ioutil.WriteFile(out, code.Bytes(), 0644)
+}
@@ -0,0 +1,83 @@
+//
+// Command stemrpc serves the Stemmer service of proto/stemmer.proto over
+// gRPC, the same stems cmd/stemd serves over HTTP:
+//
+//    Stem        the stem of a word
+//    StemBatch   the stems of words, in their order
+//    Analyze     a stream of texts gets the stream of their tokens, by the
+//                English analysis chain of package analysis
+//
+// Stem and StemBatch use the stemmer named by the algorithm of the request, or
+// -algorithm without one. A StemBatch request with more than -max-words words
+// is refused. On SIGINT or SIGTERM stemrpc stops accepting connections and
+// waits for the calls in progress, up to -shutdown-timeout.
+//
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/pigi72333/stemmer"
+	"github.com/pigi72333/stemmer/proto/stemmerpb"
+)
+
+func main() {
+	addr := flag.String("addr", ":9090", "listen on `address`")
+	algorithm := flag.String("algorithm", "porter", "stem with the algorithm registered as `name` by default")
+	maxWords := flag.Int("max-words", 10000, "refuse StemBatch requests with more than `n` words")
+	timeout := flag.Duration("shutdown-timeout", 10*time.Second, "wait up to `duration` for the calls in progress on shutdown")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: stemrpc [flags]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if _, ok := stemmer.Lookup(*algorithm); !ok {
+		fmt.Fprintf(os.Stderr, "stemrpc: unknown algorithm %q\n", *algorithm)
+		os.Exit(2)
+	}
+
+	lis, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatalf("stemrpc: %v", err)
+	}
+	srv := grpc.NewServer()
+	stemmerpb.RegisterStemmerServer(srv, newServer(*algorithm, *maxWords))
+
+	done := make(chan struct{})
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		<-sig
+		stopped := make(chan struct{})
+		go func() {
+			srv.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(*timeout):
+			log.Printf("stemrpc: shutdown: calls still in progress after %v", *timeout)
+			srv.Stop()
+		}
+		close(done)
+	}()
+
+	log.Printf("stemrpc: listening on %s", lis.Addr())
+	if err := srv.Serve(lis); err != nil {
+		log.Fatalf("stemrpc: %v", err)
+	}
+	<-done
+}
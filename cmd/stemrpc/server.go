@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pigi72333/stemmer"
+	"github.com/pigi72333/stemmer/analysis"
+	"github.com/pigi72333/stemmer/proto/stemmerpb"
+)
+
+//
+// server implements the Stemmer service of proto/stemmer.proto.
+//
+type server struct {
+	stemmerpb.UnimplementedStemmerServer
+
+	// algorithm is the stemmer used when a request does not name one.
+	algorithm string
+	// maxWords is the most words of a StemBatch request.
+	maxWords int
+	analyzer *analysis.Analyzer
+}
+
+func newServer(algorithm string, maxWords int) *server {
+	return &server{
+		algorithm: algorithm,
+		maxWords:  maxWords,
+		analyzer:  analysis.English(),
+	}
+}
+
+//
+// Stem answers Stem with the stem of the word.
+//
+func (s *server) Stem(ctx context.Context, req *stemmerpb.StemRequest) (*stemmerpb.StemResponse, error) {
+	st, err := s.stemmer(req.GetAlgorithm())
+	if err != nil {
+		return nil, err
+	}
+	return &stemmerpb.StemResponse{Stem: st.StemString(req.GetWord())}, nil
+}
+
+//
+// StemBatch answers StemBatch with the stems of the words, in their order.
+//
+func (s *server) StemBatch(ctx context.Context, req *stemmerpb.StemBatchRequest) (*stemmerpb.StemBatchResponse, error) {
+	if len(req.GetWords()) > s.maxWords {
+		return nil, status.Errorf(codes.ResourceExhausted, "more than %d words", s.maxWords)
+	}
+	st, err := s.stemmer(req.GetAlgorithm())
+	if err != nil {
+		return nil, err
+	}
+	stems := make([]string, len(req.GetWords()))
+	for i, word := range req.GetWords() {
+		stems[i] = st.StemString(word)
+	}
+	return &stemmerpb.StemBatchResponse{Stems: stems}, nil
+}
+
+//
+// Analyze sends the tokens of each text it receives, by the English analysis
+// chain, until the client closes its side of the stream.
+//
+func (s *server) Analyze(stream stemmerpb.Stemmer_AnalyzeServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		tokens := s.analyzer.Analyze(req.GetText())
+		resp := &stemmerpb.AnalyzeResponse{Tokens: make([]*stemmerpb.Token, len(tokens))}
+		for i, tok := range tokens {
+			resp.Tokens[i] = &stemmerpb.Token{
+				Term:              tok.Term,
+				Start:             int32(tok.Start),
+				End:               int32(tok.End),
+				PositionIncrement: int32(tok.PositionIncrement),
+				Type:              tok.Type.String(),
+			}
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+//
+// stemmer returns the stemmer registered as name, or the default one if name
+// is empty.
+//
+func (s *server) stemmer(name string) (stemmer.Stemmer, error) {
+	if name == "" {
+		name = s.algorithm
+	}
+	st, ok := stemmer.Lookup(name)
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("unknown algorithm %q, have %s", name, strings.Join(stemmer.Names(), ", ")))
+	}
+	return st, nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/pigi72333/stemmer/analysis"
+	"github.com/pigi72333/stemmer/client"
+	"github.com/pigi72333/stemmer/proto/stemmerpb"
+	"github.com/pigi72333/stemmer/tokenizer"
+)
+
+// dial serves a server over an in-memory connection and returns a client of
+// it, both stopped at the end of the test.
+func dial(t *testing.T, s *server) *client.Client {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	stemmerpb.RegisterStemmerServer(srv, s)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	c, err := client.Dial("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("client.Dial() returned an error: '%v'", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestStem(t *testing.T) {
+	c := dial(t, newServer("porter", 3))
+	ctx := context.Background()
+
+	fixtures := []struct {
+		algorithm, word string
+	}{
+		{"", "caresses"},
+		{"", "Ponies"},
+		{"porter2", "generously"},
+		{"s", "ponies"},
+		{"lancaster", "generously"},
+	}
+
+	stemmed := []string{
+		"caress",
+		"poni",
+		"generous",
+		"pony",
+		"gen",
+	}
+
+	for k, value := range fixtures {
+		c.Algorithm = value.algorithm
+		result, err := c.Stem(ctx, value.word)
+		if err != nil {
+			t.Fatalf("Client.Stem() returned an error: '%v'", err)
+		}
+		if result != stemmed[k] {
+			t.Errorf("Client.Stem() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value.word, result, stemmed[k])
+		}
+	}
+
+	c.Algorithm = "klingon"
+	if _, err := c.Stem(ctx, "ponies"); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Client.Stem() error not what was expected, return: '%v' expected: '%v'", err, codes.InvalidArgument)
+	}
+}
+
+func TestStemBatch(t *testing.T) {
+	c := dial(t, newServer("porter", 3))
+	ctx := context.Background()
+
+	stems, err := c.StemBatch(ctx, []string{"caresses", "ponies", "tree"})
+	if err != nil {
+		t.Fatalf("Client.StemBatch() returned an error: '%v'", err)
+	}
+	expected := []string{"caress", "poni", "tree"}
+	if len(stems) != len(expected) {
+		t.Fatalf("Client.StemBatch() return value not what was expected, return: '%v' expected: '%v'", stems, expected)
+	}
+	for i := range expected {
+		if stems[i] != expected[i] {
+			t.Errorf("Client.StemBatch() return value not what was expected, return: '%v' expected: '%v'", stems, expected)
+		}
+	}
+
+	if stems, err := c.StemBatch(ctx, nil); err != nil || len(stems) != 0 {
+		t.Errorf("Client.StemBatch() return value not what was expected, return: '%v' '%v' expected: '[]'", stems, err)
+	}
+	if _, err := c.StemBatch(ctx, []string{"a", "b", "c", "d"}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Client.StemBatch() error not what was expected, return: '%v' expected: '%v'", err, codes.ResourceExhausted)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	c := dial(t, newServer("porter", 3))
+	stream, err := c.Analyze(context.Background())
+	if err != nil {
+		t.Fatalf("Client.Analyze() returned an error: '%v'", err)
+	}
+
+	fixtures := []string{
+		"The ponies were running!",
+		"",
+		"Email bob@example.com about #stemming",
+	}
+
+	analyzed := [][]analysis.Token{
+		{
+			{Term: "poni", Start: 4, End: 10, PositionIncrement: 2, Type: tokenizer.Word},
+			{Term: "run", Start: 16, End: 23, PositionIncrement: 2, Type: tokenizer.Word},
+		},
+		{},
+		{
+			{Term: "email", Start: 0, End: 5, PositionIncrement: 1, Type: tokenizer.Word},
+			{Term: "bob@example.com", Start: 6, End: 21, PositionIncrement: 1, Type: tokenizer.Email},
+			{Term: "#stemming", Start: 28, End: 37, PositionIncrement: 2, Type: tokenizer.Hashtag},
+		},
+	}
+
+	// The texts are all sent before the tokens are read: the stream goes
+	// both ways at once.
+	for _, value := range fixtures {
+		if err := stream.Send(value); err != nil {
+			t.Fatalf("AnalyzeStream.Send() returned an error: '%v'", err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("AnalyzeStream.CloseSend() returned an error: '%v'", err)
+	}
+	for k, value := range fixtures {
+		tokens, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("AnalyzeStream.Recv() returned an error: '%v'", err)
+		}
+		if len(tokens) != len(analyzed[k]) {
+			t.Errorf("AnalyzeStream.Recv() return value not what was expected, pass: '%s' return: '%+v' expected: '%+v'", value, tokens, analyzed[k])
+			continue
+		}
+		for i := range tokens {
+			if tokens[i] != analyzed[k][i] {
+				t.Errorf("AnalyzeStream.Recv() return value not what was expected, pass: '%s' return: '%+v' expected: '%+v'", value, tokens[i], analyzed[k][i])
+			}
+		}
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("AnalyzeStream.Recv() error not what was expected, return: '%v' expected: '%v'", err, io.EOF)
+	}
+}
@@ -0,0 +1,282 @@
+package stemmer
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strings"
+)
+
+//
+// GenerateGo writes Go code that runs the rules of rs like a RuleStemmer, but
+// with each step compiled into a switch on the last byte of the word, the
+// rules of a case tried longest first, and the conditions compiled into calls
+// of the functions they are defined with. The code belongs to package
+// stemmer. It declares a function per step, named after rs.Name and the step,
+// e.g. porterStep1a, and one that runs all the steps, e.g. porterRun, on a
+// lower case word, rewriting it in place. source names the rule file in the
+// header of the code.
+//
+// cmd/stemgen runs GenerateGo for go generate: porter_gen.go is the code of
+// rules/porter.json, which Stem runs.
+//
+func GenerateGo(w io.Writer, rs *RuleSet, source string) error {
+	prefix := goIdentifier(rs.Name)
+	if prefix == "" || !isConditionLetter(prefix[0]) {
+		return fmt.Errorf("stemmer: cannot name functions after rule set %q", rs.Name)
+	}
+	g := &codeGenerator{prefix: strings.ToLower(prefix[:1]) + prefix[1:], names: make(map[*Step]string)}
+
+	fmt.Fprintf(&g.b, "// Code generated by stemgen from %s. DO NOT EDIT.\n\npackage stemmer\n", source)
+	run := g.prefix + "Run"
+	fmt.Fprintf(&g.b, "\n// %s runs the steps of %s on word, which must be lower case.\n", run, rs.Name)
+	fmt.Fprintf(&g.b, "func %s(word []byte) []byte {\nif len(word) < 3 {\nreturn word\n}\n", run)
+	for i := range rs.Steps {
+		name, err := g.name(&rs.Steps[i])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.b, "word = %s(word)\n", name)
+	}
+	g.b.WriteString("return word\n}\n")
+	for i := 0; i < len(g.steps); i++ {
+		if err := g.step(g.steps[i]); err != nil {
+			return err
+		}
+	}
+
+	src, err := format.Source(g.b.Bytes())
+	if err != nil {
+		return fmt.Errorf("stemmer: generated code does not parse: %v", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+//
+// hasSuffix reports whether word ends with suffix. The generated code calls
+// it with the suffixes of the rules.
+//
+func hasSuffix(word []byte, suffix string) bool {
+	return len(word) >= len(suffix) && string(word[len(word)-len(suffix):]) == suffix
+}
+
+type codeGenerator struct {
+	b      bytes.Buffer
+	prefix string
+	// names holds the function of each step; steps are the steps in the
+	// order their functions are generated.
+	names map[*Step]string
+	steps []*Step
+}
+
+//
+// name returns the name of the function of step, queueing it to be generated.
+//
+func (g *codeGenerator) name(step *Step) (string, error) {
+	if name, ok := g.names[step]; ok {
+		return name, nil
+	}
+	id := goIdentifier(step.Name)
+	if id == "" {
+		return "", fmt.Errorf("stemmer: cannot name a function after step %q", step.Name)
+	}
+	name := g.prefix + "Step" + id
+	for _, other := range g.names {
+		if other == name {
+			return "", fmt.Errorf("stemmer: two steps are named %q", step.Name)
+		}
+	}
+	g.names[step] = name
+	g.steps = append(g.steps, step)
+	return name, nil
+}
+
+func (g *codeGenerator) step(step *Step) error {
+	var empty *Rule
+	byLast := make(map[byte][]Rule)
+	for _, rule := range step.Rules {
+		rule.Suffix = strings.ToLower(rule.Suffix)
+		rule.Replacement = strings.ToLower(rule.Replacement)
+		if rule.Suffix == "" {
+			r := rule
+			empty = &r
+			continue
+		}
+		last := rule.Suffix[len(rule.Suffix)-1]
+		byLast[last] = append(byLast[last], rule)
+	}
+	var lasts []byte
+	for c := range byLast {
+		lasts = append(lasts, c)
+	}
+	sort.Slice(lasts, func(i, j int) bool { return lasts[i] < lasts[j] })
+
+	name := g.names[step]
+	fmt.Fprintf(&g.b, "\n// %s runs step %s.\nfunc %s(word []byte) []byte {\n", name, step.Name, name)
+	if len(lasts) > 0 {
+		g.b.WriteString("if len(word) > 0 {\nswitch word[len(word)-1] {\n")
+		for _, c := range lasts {
+			rules := byLast[c]
+			sort.SliceStable(rules, func(i, j int) bool { return len(rules[i].Suffix) > len(rules[j].Suffix) })
+			fmt.Fprintf(&g.b, "case %s:\n", byteLiteral(c))
+			for _, rule := range rules {
+				// The case has matched a suffix of one byte.
+				check := len(rule.Suffix) > 1
+				if check {
+					fmt.Fprintf(&g.b, "if hasSuffix(word, %q) {\n", rule.Suffix)
+				}
+				if err := g.rule(step, rule); err != nil {
+					return err
+				}
+				if check {
+					g.b.WriteString("}\n")
+				}
+			}
+		}
+		g.b.WriteString("}\n}\n")
+	}
+	if empty != nil {
+		if err := g.rule(step, *empty); err != nil {
+			return err
+		}
+	} else {
+		g.b.WriteString("return word\n")
+	}
+	g.b.WriteString("}\n")
+	return nil
+}
+
+//
+// rule writes the code of a rule whose suffix has matched: it returns the
+// word the rule leaves.
+//
+func (g *codeGenerator) rule(step *Step, rule Rule) error {
+	cond, err := parseCondition(rule.Condition)
+	if err != nil {
+		return fmt.Errorf("stemmer: step %s: suffix %q: %v", step.Name, rule.Suffix, err)
+	}
+
+	// Only the part of the replacement that differs from the suffix is
+	// written: SSES -> SS cuts two bytes, AT -> ATE appends one.
+	keep := 0
+	for keep < len(rule.Suffix) && keep < len(rule.Replacement) && rule.Suffix[keep] == rule.Replacement[keep] {
+		keep++
+	}
+	// A condition needs the stem, which is also the start of the result
+	// when the whole suffix goes.
+	stem := cond.op != condTrue && rule.Suffix != ""
+	result := "word"
+	if cut := len(rule.Suffix) - keep; stem && cut == len(rule.Suffix) {
+		result = "stem"
+	} else if cut > 0 {
+		result = fmt.Sprintf("word[:len(word)-%d]", cut)
+	}
+	if add := rule.Replacement[keep:]; add != "" {
+		result = fmt.Sprintf("append(%s, %q...)", result, add)
+	}
+	if rule.Then != nil {
+		then, err := g.name(rule.Then)
+		if err != nil {
+			return err
+		}
+		result = fmt.Sprintf("%s(%s)", then, result)
+	}
+
+	if cond.op == condTrue {
+		fmt.Fprintf(&g.b, "return %s\n", result)
+		return nil
+	}
+	v := "word"
+	if stem {
+		v = "stem"
+		fmt.Fprintf(&g.b, "stem := word[:len(word)-%d]\n", len(rule.Suffix))
+	}
+	if measures(cond) > 1 {
+		fmt.Fprintf(&g.b, "if m := measure(%s); %s {\n", v, goCondition(cond, v, "m", 0))
+	} else {
+		fmt.Fprintf(&g.b, "if %s {\n", goCondition(cond, v, "", 0))
+	}
+	fmt.Fprintf(&g.b, "return %s\n}\nreturn word\n", result)
+	return nil
+}
+
+//
+// measures returns how many times c looks at the measure of the stem.
+//
+func measures(c *condition) int {
+	switch c.op {
+	case condMeasure:
+		return 1
+	case condNot:
+		return measures(c.x)
+	case condAnd, condOr:
+		return measures(c.x) + measures(c.y)
+	}
+	return 0
+}
+
+//
+// goCondition returns c as a Go expression on the variable stem, with the
+// measure in the variable m if it is not empty. prec is the precedence the
+// expression needs to have not to be put in parentheses: 1 for ||, 2 for &&,
+// 3 for comparisons and 4 for the operand of !.
+//
+func goCondition(c *condition, stem, m string, prec int) string {
+	var s string
+	own := 4
+	switch c.op {
+	case condMeasure:
+		if m == "" {
+			m = "measure(" + stem + ")"
+		}
+		cmp := c.cmp
+		if cmp == "=" {
+			cmp = "=="
+		}
+		s, own = fmt.Sprintf("%s %s %d", m, cmp, c.n), 3
+	case condVowel:
+		s = "containVowel(" + stem + ")"
+	case condDouble:
+		s = "endsDouble(" + stem + ")"
+	case condCVC:
+		s = "isCVCSuffix(" + stem + ")"
+	case condEnds:
+		s = fmt.Sprintf("endsWithAny(%s, %q)", stem, c.letters)
+	case condNot:
+		s = "!" + goCondition(c.x, stem, m, 4)
+	case condAnd:
+		s, own = goCondition(c.x, stem, m, 2)+" && "+goCondition(c.y, stem, m, 2), 2
+	case condOr:
+		s, own = goCondition(c.x, stem, m, 1)+" || "+goCondition(c.y, stem, m, 1), 1
+	default:
+		s = "true"
+	}
+	if own < prec {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+//
+// goIdentifier returns s with the bytes that cannot be in a Go identifier
+// replaced by underscores.
+//
+func goIdentifier(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if !isConditionLetter(c) && !('0' <= c && c <= '9') {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+func byteLiteral(c byte) string {
+	if c >= ' ' && c < 0x7f && c != '\'' && c != '\\' {
+		return "'" + string(c) + "'"
+	}
+	return fmt.Sprintf("0x%02x", c)
+}
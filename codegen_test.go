@@ -0,0 +1,83 @@
+package stemmer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+//
+// porter_gen.go must be what go generate writes for rules/porter.json; Stem
+// runs it, so TestVocal checks it against output.txt.
+//
+func TestGenerateGo(t *testing.T) {
+	data, err := ioutil.ReadFile("rules/porter.json")
+	if err != nil {
+		panic(err)
+	}
+	rs, err := ParseRules("rules/porter.json", data)
+	if err != nil {
+		t.Fatalf("ParseRules() returned an error: '%v'", err)
+	}
+	var code bytes.Buffer
+	if err := GenerateGo(&code, rs, "rules/porter.json"); err != nil {
+		t.Fatalf("GenerateGo() returned an error: '%v'", err)
+	}
+	expected, err := ioutil.ReadFile("porter_gen.go")
+	if err != nil {
+		panic(err)
+	}
+	if !bytes.Equal(code.Bytes(), expected) {
+		t.Errorf("GenerateGo() return value not what was expected: porter_gen.go is out of date, run go generate")
+	}
+}
+
+func TestGoCondition(t *testing.T) {
+	fixtures := []string{
+		"",
+		"m>0",
+		"m=1 and *o",
+		"m>1 and (*S or *T)",
+		"not (*L or *S)",
+		"not m>1",
+		"*d and not *[lsz]",
+		"*v* or m>1 and *e",
+	}
+
+	expressions := []string{
+		"true",
+		"measure(stem) > 0",
+		"measure(stem) == 1 && isCVCSuffix(stem)",
+		"measure(stem) > 1 && (endsWithAny(stem, \"s\") || endsWithAny(stem, \"t\"))",
+		"!(endsWithAny(stem, \"l\") || endsWithAny(stem, \"s\"))",
+		"!(measure(stem) > 1)",
+		"endsDouble(stem) && !endsWithAny(stem, \"lsz\")",
+		"containVowel(stem) || measure(stem) > 1 && endsWithAny(stem, \"e\")",
+	}
+
+	for k, value := range fixtures {
+		c, err := parseCondition(value)
+		if err != nil {
+			t.Fatalf("parseCondition() returned an error: '%v'", err)
+		}
+		if result := goCondition(c, "stem", "", 0); result != expressions[k] {
+			t.Errorf("goCondition() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, expressions[k])
+		}
+	}
+}
+
+func TestGenerateGoError(t *testing.T) {
+	fixtures := []*RuleSet{
+		{Name: "", Steps: []Step{{Name: "1", Rules: []Rule{{Suffix: "s"}}}}},
+		{Name: "2nd", Steps: []Step{{Name: "1", Rules: []Rule{{Suffix: "s"}}}}},
+		{Name: "x", Steps: []Step{{Name: "", Rules: []Rule{{Suffix: "s"}}}}},
+		{Name: "x", Steps: []Step{{Name: "1", Rules: []Rule{{Suffix: "s", Condition: "m>"}}}}},
+		{Name: "x", Steps: []Step{{Name: "1-a"}, {Name: "1_a"}}},
+	}
+
+	for _, value := range fixtures {
+		if err := GenerateGo(ioutil.Discard, value, "test.json"); err == nil {
+			t.Errorf("GenerateGo() did not return an error, pass: '%+v'", value)
+		}
+	}
+}
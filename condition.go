@@ -0,0 +1,296 @@
+package stemmer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+//
+// Conditions are written the way the paper writes them:
+//
+//    m>1                   the measure of the stem compares to a number, with
+//                          one of =, !=, <, <=, > or >=
+//    *v*                   the stem contains a vowel
+//    *d                    the stem ends with a double consonant
+//    *o                    the stem ends cvc, where the second c is not W, X
+//                          or Y
+//    *S                    the stem ends with S, and similarly for the other
+//                          letters
+//    *[LSZ]                the stem ends with one of the letters L, S or Z
+//
+// combined with not, and, or and parentheses, e.g. "m>1 and (*S or *T)". The
+// empty condition always holds.
+//
+
+type conditionOp int
+
+const (
+	condTrue conditionOp = iota
+	condMeasure
+	condVowel
+	condDouble
+	condCVC
+	condEnds
+	condNot
+	condAnd
+	condOr
+)
+
+type condition struct {
+	op conditionOp
+	// cmp and n are the comparison and the number of condMeasure.
+	cmp string
+	n   int
+	// letters are the letters of condEnds, in lower case.
+	letters string
+	// x and y are the operands of condNot, condAnd and condOr.
+	x, y *condition
+}
+
+func (c *condition) eval(stem []byte) bool {
+	switch c.op {
+	case condMeasure:
+		m := measure(stem)
+		switch c.cmp {
+		case "=":
+			return m == c.n
+		case "!=":
+			return m != c.n
+		case "<":
+			return m < c.n
+		case "<=":
+			return m <= c.n
+		case ">":
+			return m > c.n
+		default:
+			return m >= c.n
+		}
+	case condVowel:
+		return containVowel(stem)
+	case condDouble:
+		return endsDouble(stem)
+	case condCVC:
+		return isCVCSuffix(stem)
+	case condEnds:
+		return endsWithAny(stem, c.letters)
+	case condNot:
+		return !c.x.eval(stem)
+	case condAnd:
+		return c.x.eval(stem) && c.y.eval(stem)
+	case condOr:
+		return c.x.eval(stem) || c.y.eval(stem)
+	}
+	return true
+}
+
+//
+// *d - the stem ends with a double consonant
+//
+func endsDouble(stem []byte) bool {
+	l := len(stem)
+	if l > 0 && stem[l-1] >= utf8.RuneSelf {
+		last, size := utf8.DecodeLastRune(stem)
+		prev, _ := utf8.DecodeLastRune(stem[:l-size])
+		return last == prev && consonant(stem, l-1)
+	}
+	return l >= 2 && stem[l-1] == stem[l-2] && consonant(stem, l-1)
+}
+
+//
+// *S - the stem ends with one of letters
+//
+func endsWithAny(stem []byte, letters string) bool {
+	return len(stem) > 0 && strings.IndexByte(letters, stem[len(stem)-1]) >= 0
+}
+
+//
+// ConditionError reports a condition that could not be parsed.
+//
+type ConditionError struct {
+	Condition string
+	// Offset is the byte offset in Condition where the problem was found.
+	Offset int
+	Msg    string
+}
+
+func (e *ConditionError) Error() string {
+	return fmt.Sprintf("stemmer: condition %q at offset %d: %s", e.Condition, e.Offset, e.Msg)
+}
+
+func parseCondition(s string) (*condition, error) {
+	p := &conditionParser{s: s}
+	p.next()
+	if p.tok == "" {
+		return &condition{op: condTrue}, nil
+	}
+	c, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok != "" {
+		return nil, p.errorf("unexpected %q", p.tok)
+	}
+	return c, nil
+}
+
+type conditionParser struct {
+	s   string
+	pos int
+	// tok is the current token and off its offset in s; tok is empty at the
+	// end of the input.
+	tok string
+	off int
+}
+
+func (p *conditionParser) errorf(format string, args ...interface{}) error {
+	return &ConditionError{Condition: p.s, Offset: p.off, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *conditionParser) next() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+	p.off = p.pos
+	if p.pos == len(p.s) {
+		p.tok = ""
+		return
+	}
+	end := p.pos + 1
+	switch c := p.s[p.pos]; {
+	case c == '(' || c == ')':
+	case c == '*':
+		if end < len(p.s) && p.s[end] == '[' {
+			for end < len(p.s) && p.s[end] != ']' {
+				end++
+			}
+			if end < len(p.s) {
+				end++
+			}
+		} else {
+			for end < len(p.s) && isConditionLetter(p.s[end]) {
+				end++
+			}
+			if end < len(p.s) && p.s[end] == '*' {
+				end++
+			}
+		}
+	case c == '=' || c == '!' || c == '<' || c == '>':
+		if end < len(p.s) && p.s[end] == '=' {
+			end++
+		}
+	case '0' <= c && c <= '9':
+		for end < len(p.s) && '0' <= p.s[end] && p.s[end] <= '9' {
+			end++
+		}
+	case isConditionLetter(c):
+		for end < len(p.s) && isConditionLetter(p.s[end]) {
+			end++
+		}
+	}
+	p.tok = p.s[p.pos:end]
+	p.pos = end
+}
+
+func isConditionLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func (p *conditionParser) or() (*condition, error) {
+	x, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok == "or" {
+		p.next()
+		y, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		x = &condition{op: condOr, x: x, y: y}
+	}
+	return x, nil
+}
+
+func (p *conditionParser) and() (*condition, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok == "and" {
+		p.next()
+		y, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		x = &condition{op: condAnd, x: x, y: y}
+	}
+	return x, nil
+}
+
+func (p *conditionParser) unary() (*condition, error) {
+	tok := p.tok
+	switch {
+	case tok == "":
+		return nil, p.errorf("unexpected end of condition")
+	case tok == "not":
+		p.next()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &condition{op: condNot, x: x}, nil
+	case tok == "(":
+		p.next()
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok != ")" {
+			return nil, p.errorf("missing )")
+		}
+		p.next()
+		return x, nil
+	case tok == "m":
+		p.next()
+		cmp := p.tok
+		switch cmp {
+		case "=", "!=", "<", "<=", ">", ">=":
+		default:
+			return nil, p.errorf("expected a comparison after m, found %q", cmp)
+		}
+		p.next()
+		n, err := strconv.Atoi(p.tok)
+		if err != nil {
+			return nil, p.errorf("expected a number after m%s, found %q", cmp, p.tok)
+		}
+		p.next()
+		return &condition{op: condMeasure, cmp: cmp, n: n}, nil
+	case tok == "*v*":
+		p.next()
+		return &condition{op: condVowel}, nil
+	case tok == "*d":
+		p.next()
+		return &condition{op: condDouble}, nil
+	case tok == "*o":
+		p.next()
+		return &condition{op: condCVC}, nil
+	case strings.HasPrefix(tok, "*["):
+		letters := strings.ToLower(strings.TrimSuffix(tok[2:], "]"))
+		if !strings.HasSuffix(tok, "]") || letters == "" {
+			return nil, p.errorf("bad letter set %q", tok)
+		}
+		for i := 0; i < len(letters); i++ {
+			if !isConditionLetter(letters[i]) {
+				return nil, p.errorf("bad letter set %q", tok)
+			}
+		}
+		p.next()
+		return &condition{op: condEnds, letters: letters}, nil
+	case len(tok) == 2 && tok[0] == '*' && isConditionLetter(tok[1]):
+		p.next()
+		return &condition{op: condEnds, letters: strings.ToLower(tok[1:])}, nil
+	}
+	return nil, p.errorf("unexpected %q", tok)
+}
@@ -0,0 +1,75 @@
+package stemmer
+
+//
+// The Danish stemmer:
+//
+//    https://snowballstem.org/algorithms/danish/stemmer.html
+//
+
+const danishVowels = "aeiouyæåø"
+
+var danishMainSuffixes = newSuffixList(
+	"hed", "ethed", "ered", "e", "erede", "ende", "erende", "ene", "erne",
+	"ere", "en", "heden", "eren", "er", "heder", "erer", "heds", "es",
+	"endes", "erendes", "enes", "ernes", "eres", "ens", "hedens", "erens",
+	"ers", "ets", "erets", "et", "eret", "s",
+)
+
+var danishOtherSuffixes = newSuffixList("ig", "lig", "elig", "els", "løst")
+
+//
+// scandinavianR1 is R1, moved so that at least three letters come before
+// it.
+//
+func scandinavianR1(w []rune, vowels string) int {
+	if len(w) < 3 {
+		return len(w)
+	}
+	r1 := regionAfter(w, 0, vowels)
+	if r1 < 3 {
+		r1 = 3
+	}
+	return r1
+}
+
+func stemDanish(w []rune) []rune {
+	r1 := scandinavianR1(w, danishVowels)
+
+	// Step 1: the main suffixes in R1; s only after a valid s-ending.
+	if s, start := danishMainSuffixes.find(w, r1); s == "s" {
+		if inGroup(w, start-1, "abcdfghjklmnoprtvyzå") {
+			w = w[:start]
+		}
+	} else if s != "" {
+		w = w[:start]
+	}
+
+	// Step 2: undouble gd, dt, gt and kt in R1.
+	w = danishConsonantPair(w, r1)
+
+	// Step 3: igst loses its st, then the other suffixes in R1.
+	if endsWith(w, "igst") {
+		w = w[:len(w)-2]
+	}
+	switch s, start := danishOtherSuffixes.find(w, r1); s {
+	case "ig", "lig", "elig", "els":
+		w = danishConsonantPair(w[:start], r1)
+	case "løst":
+		w = w[:len(w)-1]
+	}
+
+	// Step 4: undouble a final consonant in R1.
+	if n := len(w); n-1 >= r1 && n >= 2 && !inGroup(w, n-1, danishVowels) && w[n-1] == w[n-2] {
+		w = w[:n-1]
+	}
+	return w
+}
+
+func danishConsonantPair(w []rune, r1 int) []rune {
+	for _, s := range []string{"gd", "dt", "gt", "kt"} {
+		if start := suffixStart(w, s); start >= r1 {
+			return w[:len(w)-1]
+		}
+	}
+	return w
+}
@@ -0,0 +1,155 @@
+package stemmer
+
+//
+// The Dutch stemmer:
+//
+//    https://snowballstem.org/algorithms/dutch/stemmer.html
+//
+// The accents are removed first, and an initial y, a y after a vowel and an
+// i between vowels are kept as an upper case Y or I while stemming, so that
+// they count as consonants.
+//
+
+const dutchVowels = "aeiouyè"
+
+var (
+	dutchStep1Suffixes  = newSuffixList("heden", "en", "ene", "s", "se")
+	dutchStep3bSuffixes = newSuffixList("end", "ing", "ig", "lijk", "baar", "bar")
+)
+
+func stemDutch(w []rune) []rune {
+	w = dutchPrelude(w)
+	r1 := regionAfter(w, 0, dutchVowels)
+	r2 := regionAfter(w, r1, dutchVowels)
+	if r1 < 3 {
+		r1 = 3
+	}
+
+	// Step 1: heden becomes heid, en and ene go after a valid en-ending, s
+	// and se after a valid s-ending, in R1.
+	switch s, start := dutchStep1Suffixes.find(w, 0); s {
+	case "heden":
+		if start >= r1 {
+			w = replaceSuffix(w, start, "heid")
+		}
+	case "en", "ene":
+		w = dutchENEnding(w, start, r1)
+	case "s", "se":
+		if start >= r1 && start > 0 && !inGroup(w, start-1, dutchVowels) && w[start-1] != 'j' {
+			w = w[:start]
+		}
+	}
+
+	// Step 2: e after a non-vowel in R1, then undouble.
+	eFound := false
+	if start := suffixStart(w, "e"); start >= r1 && start > 0 && !inGroup(w, start-1, dutchVowels) {
+		w = dutchUndouble(w[:start])
+		eFound = true
+	}
+
+	// Step 3a: heid in R2 not after c, then en as in step 1.
+	if start := suffixStart(w, "heid"); start >= r2 && !inGroup(w, start-1, "c") {
+		w = w[:start]
+		if start := suffixStart(w, "en"); start >= 0 {
+			w = dutchENEnding(w, start, r1)
+		}
+	}
+
+	// Step 3b: the derivational suffixes in R2.
+	switch s, start := dutchStep3bSuffixes.find(w, 0); s {
+	case "end", "ing":
+		if start >= r2 {
+			w = w[:start]
+			if start := suffixStart(w, "ig"); start >= r2 && !inGroup(w, start-1, "e") {
+				w = w[:start]
+			} else {
+				w = dutchUndouble(w)
+			}
+		}
+	case "ig":
+		if start >= r2 && !inGroup(w, start-1, "e") {
+			w = w[:start]
+		}
+	case "lijk":
+		if start >= r2 {
+			w = w[:start]
+			if start := suffixStart(w, "e"); start >= r1 && start > 0 && !inGroup(w, start-1, dutchVowels) {
+				w = dutchUndouble(w[:start])
+			}
+		}
+	case "baar":
+		if start >= r2 {
+			w = w[:start]
+		}
+	case "bar":
+		if start >= r2 && eFound {
+			w = w[:start]
+		}
+	}
+
+	// Step 4: undouble the vowel of a final consonant, double vowel and
+	// consonant other than I.
+	if n := len(w); n >= 4 && !inGroup(w, n-4, dutchVowels) && inGroup(w, n-3, "aeou") &&
+		w[n-3] == w[n-2] && !inGroup(w, n-1, dutchVowels) && w[n-1] != 'I' {
+		w = append(w[:n-2], w[n-1])
+	}
+
+	for i, r := range w {
+		switch r {
+		case 'Y':
+			w[i] = 'y'
+		case 'I':
+			w[i] = 'i'
+		}
+	}
+	return w
+}
+
+//
+// dutchENEnding removes the en or ene starting at start if it is in R1 after
+// a non-vowel other than the m of gem, then undoubles.
+//
+func dutchENEnding(w []rune, start, r1 int) []rune {
+	if start < r1 || start == 0 || inGroup(w, start-1, dutchVowels) || endsWith(w[:start], "gem") {
+		return w
+	}
+	return dutchUndouble(w[:start])
+}
+
+func dutchUndouble(w []rune) []rune {
+	for _, s := range []string{"kk", "dd", "tt"} {
+		if endsWith(w, s) {
+			return w[:len(w)-1]
+		}
+	}
+	return w
+}
+
+func dutchPrelude(w []rune) []rune {
+	for i, r := range w {
+		switch r {
+		case 'ä', 'á':
+			w[i] = 'a'
+		case 'ë', 'é':
+			w[i] = 'e'
+		case 'ï', 'í':
+			w[i] = 'i'
+		case 'ö', 'ó':
+			w[i] = 'o'
+		case 'ü', 'ú':
+			w[i] = 'u'
+		}
+	}
+	if len(w) > 0 && w[0] == 'y' {
+		w[0] = 'Y'
+	}
+	for i := 1; i < len(w); i++ {
+		switch {
+		case w[i] == 'y' && inGroup(w, i-1, dutchVowels):
+			w[i] = 'Y'
+		case w[i] == 'i' && inGroup(w, i-1, dutchVowels) && inGroup(w, i+1, dutchVowels):
+			w[i] = 'I'
+		}
+	}
+	return w
+}
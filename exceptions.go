@@ -0,0 +1,200 @@
+package stemmer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+//
+// Exceptions wraps a Stemmer with a list of protected words, which are
+// returned unchanged, and a list of overrides, which map a word to a given
+// stem, e.g. "mice" to "mouse". Words are looked up in lower case and without
+// surrounding white space, before any step of the wrapped stemmer runs.
+//
+// Stem and StemString are safe for concurrent use, but the lists must be
+// filled before the Exceptions is shared.
+//
+type Exceptions struct {
+	// The counters come first to keep them 64-bit aligned.
+	protectedHits uint64
+	overrideHits  uint64
+	stemmed       uint64
+
+	stemmer   Stemmer
+	protected map[string]bool
+	overrides map[string]string
+}
+
+//
+// ExceptionStats are the counters of an Exceptions.
+//
+type ExceptionStats struct {
+	// Protected and Overridden count the words found in each list, Stemmed
+	// the words passed on to the wrapped stemmer.
+	Protected  uint64
+	Overridden uint64
+	Stemmed    uint64
+}
+
+//
+// NewExceptions returns an Exceptions with empty lists wrapping s.
+//
+func NewExceptions(s Stemmer) *Exceptions {
+	return &Exceptions{
+		stemmer:   s,
+		protected: make(map[string]bool),
+		overrides: make(map[string]string),
+	}
+}
+
+//
+// Protect adds words to the protected words.
+//
+func (e *Exceptions) Protect(words ...string) {
+	for _, w := range words {
+		e.protected[exceptionKey(w)] = true
+	}
+}
+
+//
+// Override makes word stem to stem.
+//
+func (e *Exceptions) Override(word, stem string) {
+	e.overrides[exceptionKey(word)] = stem
+}
+
+//
+// exceptionKey returns word as Stem and StemString look it up: in lower case,
+// normalized and without surrounding white space.
+//
+func exceptionKey(word string) string {
+	return string(bytes.TrimSpace(appendLowerString(nil, word)))
+}
+
+//
+// LoadProtected adds the words of the file at path to the protected words,
+// see ReadProtected.
+//
+func (e *Exceptions) LoadProtected(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return e.ReadProtected(f)
+}
+
+//
+// ReadProtected adds the words read from r to the protected words. There is
+// one word per line; blank lines and lines starting with # are skipped.
+//
+func (e *Exceptions) ReadProtected(r io.Reader) error {
+	return readExceptionList(r, func(line int, fields []string) error {
+		if len(fields) != 1 {
+			return fmt.Errorf("stemmer: line %d: expected one word, found %d", line, len(fields))
+		}
+		e.Protect(fields[0])
+		return nil
+	})
+}
+
+//
+// LoadOverrides adds the overrides of the file at path, see ReadOverrides.
+//
+func (e *Exceptions) LoadOverrides(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return e.ReadOverrides(f)
+}
+
+//
+// ReadOverrides adds the overrides read from r. There is one word and its
+// stem per line, separated by blanks; blank lines and lines starting with #
+// are skipped.
+//
+func (e *Exceptions) ReadOverrides(r io.Reader) error {
+	return readExceptionList(r, func(line int, fields []string) error {
+		if len(fields) != 2 {
+			return fmt.Errorf("stemmer: line %d: expected a word and its stem, found %d fields", line, len(fields))
+		}
+		e.Override(fields[0], fields[1])
+		return nil
+	})
+}
+
+func readExceptionList(r io.Reader, add func(line int, fields []string) error) error {
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == '#' {
+			continue
+		}
+		if err := add(line, strings.Fields(text)); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+//
+// Stem returns the override or the lower case form of a protected word, and
+// the stem of the wrapped stemmer otherwise.
+//
+func (e *Exceptions) Stem(word []byte) []byte {
+	var buf [64]byte
+	lower := bytes.TrimSpace(appendLower(buf[:0], word))
+	if e.protected[string(lower)] {
+		atomic.AddUint64(&e.protectedHits, 1)
+		return append([]byte(nil), lower...)
+	}
+	if stem, ok := e.overrides[string(lower)]; ok {
+		atomic.AddUint64(&e.overrideHits, 1)
+		return []byte(stem)
+	}
+	atomic.AddUint64(&e.stemmed, 1)
+	return e.stemmer.Stem(word)
+}
+
+//
+// StemString is Stem for strings.
+//
+func (e *Exceptions) StemString(word string) string {
+	var buf [64]byte
+	lower := bytes.TrimSpace(appendLowerString(buf[:0], word))
+	if e.protected[string(lower)] {
+		atomic.AddUint64(&e.protectedHits, 1)
+		return stringResult(word, lower)
+	}
+	if stem, ok := e.overrides[string(lower)]; ok {
+		atomic.AddUint64(&e.overrideHits, 1)
+		return stem
+	}
+	atomic.AddUint64(&e.stemmed, 1)
+	return e.stemmer.StemString(word)
+}
+
+//
+// Name returns the name of the wrapped stemmer.
+//
+func (e *Exceptions) Name() string {
+	return e.stemmer.Name()
+}
+
+//
+// Stats returns the counters of e.
+//
+func (e *Exceptions) Stats() ExceptionStats {
+	return ExceptionStats{
+		Protected:  atomic.LoadUint64(&e.protectedHits),
+		Overridden: atomic.LoadUint64(&e.overrideHits),
+		Stemmed:    atomic.LoadUint64(&e.stemmed),
+	}
+}
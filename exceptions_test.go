@@ -0,0 +1,91 @@
+package stemmer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExceptions(t *testing.T) {
+	e := NewExceptions(Porter{})
+	if err := e.LoadProtected("testdata/exceptions/protected.txt"); err != nil {
+		t.Fatalf("Exceptions.LoadProtected() returned an error: '%v'", err)
+	}
+	if err := e.LoadOverrides("testdata/exceptions/overrides.txt"); err != nil {
+		t.Fatalf("Exceptions.LoadOverrides() returned an error: '%v'", err)
+	}
+
+	fixtures := []string{
+		"news",
+		"Texas",
+		"ios",
+		"mice",
+		"Went",
+		"conflated",
+		" geese ",
+		"\tNews\n",
+	}
+
+	stemmed := []string{
+		"news",
+		"texas",
+		"ios",
+		"mouse",
+		"go",
+		"conflat",
+		"goose",
+		"news",
+	}
+
+	for k, value := range fixtures {
+		if result := string(e.Stem([]byte(value))); result != stemmed[k] {
+			t.Errorf("Exceptions.Stem() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, stemmed[k])
+		}
+		if result := e.StemString(value); result != stemmed[k] {
+			t.Errorf("Exceptions.StemString() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, stemmed[k])
+		}
+	}
+
+	expected := ExceptionStats{Protected: 8, Overridden: 6, Stemmed: 2}
+	if stats := e.Stats(); stats != expected {
+		t.Errorf("Exceptions.Stats() return value not what was expected, return: '%+v' expected: '%+v'", stats, expected)
+	}
+}
+
+func TestExceptionsNFC(t *testing.T) {
+	e := NewExceptions(Porter{})
+	e.Protect("Cafe\u0301s")
+	e.Override("re\u0301sume\u0301s", "résumé")
+
+	fixtures := []string{
+		"cafés",
+		"CAFE\u0301S",
+		"résumés",
+		"re\u0301sume\u0301s",
+	}
+
+	stemmed := []string{
+		"cafés",
+		"cafés",
+		"résumé",
+		"résumé",
+	}
+
+	for k, value := range fixtures {
+		if result := string(e.Stem([]byte(value))); result != stemmed[k] {
+			t.Errorf("Exceptions.Stem() return value not what was expected, pass: '%+q' return: '%+q' expected: '%+q'", value, result, stemmed[k])
+		}
+		if result := e.StemString(value); result != stemmed[k] {
+			t.Errorf("Exceptions.StemString() return value not what was expected, pass: '%+q' return: '%+q' expected: '%+q'", value, result, stemmed[k])
+		}
+	}
+}
+
+func TestExceptionsReadError(t *testing.T) {
+	e := NewExceptions(Porter{})
+	if err := e.ReadOverrides(strings.NewReader("mice mouse\n\nwent\n")); err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Exceptions.ReadOverrides() error not what was expected, return: '%v'", err)
+	}
+	if err := e.ReadProtected(strings.NewReader("# comment\nnew york\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Exceptions.ReadProtected() error not what was expected, return: '%v'", err)
+	}
+}
@@ -0,0 +1,189 @@
+package stemmer
+
+//
+// The Finnish stemmer:
+//
+//    https://snowballstem.org/algorithms/finnish/stemmer.html
+//
+
+const finnishVowels = "aeiouyäö"
+
+var (
+	finnishParticles   = newSuffixList("kin", "kaan", "kään", "ko", "kö", "han", "hän", "pa", "pä", "sti")
+	finnishPossessives = newSuffixList("si", "ni", "nsa", "nsä", "mme", "nne", "an", "än", "en")
+	finnishCaseEndings = newSuffixList(
+		"han", "hen", "hin", "hon", "hän", "hön", "siin", "seen", "den", "tten",
+		"n", "a", "ä", "tta", "ttä", "ssa", "sta", "lla", "lta", "lle", "ksi",
+		"ine", "ta", "na", "ssä", "stä", "llä", "ltä", "tä", "nä",
+	)
+	finnishOtherEndings = newSuffixList(
+		"mpi", "mpa", "mpä", "mmi", "mma", "mmä", "impi", "impa", "impä", "immi",
+		"imma", "immä", "eja", "ejä",
+	)
+	finnishLongVowels = newSuffixList("aa", "ee", "ii", "oo", "uu", "ää", "öö")
+)
+
+func stemFinnish(w []rune) []rune {
+	r1 := regionAfter(w, 0, finnishVowels)
+	r2 := regionAfter(w, r1, finnishVowels)
+
+	// Step 1: the particles in R1; sti only in R2, the others after n, t or
+	// a vowel.
+	switch s, start := finnishParticles.find(w, r1); s {
+	case "":
+	case "sti":
+		if start >= r2 {
+			w = w[:start]
+		}
+	default:
+		if inGroup(w, start-1, "aeinotuyäö") {
+			w = w[:start]
+		}
+	}
+
+	w = finnishPossessive(w, r1)
+
+	var removed bool
+	w, removed = finnishCaseEnding(w, r1)
+
+	// Step 4: the other endings in R2, mpi and the like not after po.
+	switch s, start := finnishOtherEndings.find(w, r2); s {
+	case "":
+	case "mpi", "mpa", "mpä", "mmi", "mma", "mmä":
+		if !endsWith(w[:start], "po") {
+			w = w[:start]
+		}
+	default:
+		w = w[:start]
+	}
+
+	// Step 5: if step 3 removed an ending, a plural i or j in R1; step 6:
+	// otherwise, a plural t after a vowel in R1, and then mma in R2 if not
+	// after po, or imma in R2.
+	if removed {
+		if n := len(w); n-1 >= r1 && (w[n-1] == 'i' || w[n-1] == 'j') {
+			w = w[:n-1]
+		}
+	} else if n := len(w); n-1 >= r1 && w[n-1] == 't' && n-2 >= r1 && inGroup(w, n-2, finnishVowels) {
+		w = w[:n-1]
+		switch s, start := newSuffixList("mma", "imma").find(w, r2); s {
+		case "mma":
+			if !endsWith(w[:start], "po") {
+				w = w[:start]
+			}
+		case "imma":
+			w = w[:start]
+		}
+	}
+
+	if len(w) >= r1 {
+		w = finnishTidy(w, r1)
+	}
+	return w
+}
+
+//
+// finnishPossessive is step 2: the possessive suffixes in R1.
+//
+func finnishPossessive(w []rune, r1 int) []rune {
+	switch s, start := finnishPossessives.find(w, r1); s {
+	case "si":
+		if !inGroup(w, start-1, "k") {
+			w = w[:start]
+		}
+	case "ni":
+		w = w[:start]
+		if endsWith(w, "kse") {
+			w[len(w)-1] = 'i'
+		}
+	case "nsa", "nsä", "mme", "nne":
+		w = w[:start]
+	case "an":
+		if s, _ := newSuffixList("ta", "ssa", "sta", "lla", "lta", "na").find(w[:start], 0); s != "" {
+			w = w[:start]
+		}
+	case "än":
+		if s, _ := newSuffixList("tä", "ssä", "stä", "llä", "ltä", "nä").find(w[:start], 0); s != "" {
+			w = w[:start]
+		}
+	case "en":
+		if s, _ := newSuffixList("lle", "ine").find(w[:start], 0); s != "" {
+			w = w[:start]
+		}
+	}
+	return w
+}
+
+//
+// finnishCaseEnding is step 3: the case endings in R1. It reports whether it
+// removed one.
+//
+func finnishCaseEnding(w []rune, r1 int) ([]rune, bool) {
+	s, start := finnishCaseEndings.find(w, r1)
+	switch s {
+	case "siin", "den", "tten":
+		// Only after a vowel and i in R1.
+		if !(start-2 >= r1 && w[start-1] == 'i' && inGroup(w, start-2, "aeiouäö")) {
+			s, start = "n", len(w)-1
+		}
+	case "seen":
+		// Only after a long vowel in R1.
+		if l, _ := finnishLongVowels.find(w[:start], r1); l == "" {
+			s, start = "n", len(w)-1
+		}
+	}
+
+	switch s {
+	case "":
+		return w, false
+	case "han", "hen", "hin", "hon", "hän", "hön":
+		// Only after the vowel of the ending.
+		if !inGroup(w, start-1, string([]rune(s)[1:2])) {
+			return w, false
+		}
+	case "n":
+		// A long vowel or ie before it goes with it, less its last letter.
+		if l, _ := finnishLongVowels.find(w[:start], 0); l != "" || endsWith(w[:start], "ie") {
+			start--
+		}
+	case "a", "ä":
+		// Only after a vowel after a consonant.
+		if start < 2 || !inGroup(w, start-1, finnishVowels) || inGroup(w, start-2, finnishVowels) {
+			return w, false
+		}
+	case "tta", "ttä":
+		if !inGroup(w, start-1, "e") {
+			return w, false
+		}
+	}
+	return w[:start], true
+}
+
+//
+// finnishTidy is step 7: in R1 a long vowel is shortened, a, e, i or ä is
+// removed after a consonant, j after o or u and o after j; then the last
+// consonant of the word is undoubled.
+//
+func finnishTidy(w []rune, r1 int) []rune {
+	if l, _ := finnishLongVowels.find(w, r1); l != "" {
+		w = w[:len(w)-1]
+	}
+	if n := len(w); n-2 >= r1 && inGroup(w, n-1, "aeiä") && !inGroup(w, n-2, finnishVowels) {
+		w = w[:n-1]
+	}
+	if n := len(w); n-2 >= r1 && w[n-1] == 'j' && inGroup(w, n-2, "ou") {
+		w = w[:n-1]
+	}
+	if n := len(w); n-2 >= r1 && w[n-1] == 'o' && w[n-2] == 'j' {
+		w = w[:n-1]
+	}
+
+	i := len(w) - 1
+	for i >= 0 && inGroup(w, i, finnishVowels) {
+		i--
+	}
+	if i >= 1 && w[i-1] == w[i] {
+		w = append(w[:i], w[i+1:]...)
+	}
+	return w
+}
@@ -0,0 +1,372 @@
+package stemmer
+
+//
+// The French stemmer:
+//
+//    https://snowballstem.org/algorithms/french/stemmer.html
+//
+// A u or an i between vowels, a y before or after a vowel and the u of qu are
+// kept as an upper case U, I or Y while stemming, so that they count as
+// consonants.
+//
+
+const frenchVowels = "aeiouyâàëéêèïîôûù"
+
+var frenchStep1Suffixes = newSuffixList(
+	"ance", "iqUe", "isme", "able", "iste", "eux", "ances", "iqUes",
+	"ismes", "ables", "istes",
+	"atrice", "ateur", "ation", "atrices", "ateurs", "ations",
+	"logie", "logies",
+	"usion", "ution", "usions", "utions",
+	"ence", "ences",
+	"ement", "ements",
+	"ité", "ités",
+	"if", "ive", "ifs", "ives",
+	"eaux",
+	"aux",
+	"euse", "euses",
+	"issement", "issements",
+	"amment",
+	"emment",
+	"ment", "ments",
+)
+
+var frenchIVerbSuffixes = newSuffixList(
+	"îmes", "ît", "îtes", "i", "ie", "ies", "ir", "ira", "irai", "iraIent",
+	"irais", "irait", "iras", "irent", "irez", "iriez", "irions", "irons",
+	"iront", "is", "issaIent", "issais", "issait", "issant", "issante",
+	"issantes", "issants", "isse", "issent", "isses", "issez", "issiez",
+	"issions", "issons", "it",
+)
+
+var frenchVerbSuffixes = newSuffixList(
+	"ions",
+	"é", "ée", "ées", "és", "èrent", "er", "era", "erai", "eraIent",
+	"erais", "erait", "eras", "erez", "eriez", "erions", "erons", "eront",
+	"ez", "iez",
+	"âmes", "ât", "âtes", "a", "ai", "aIent", "ais", "ait", "ant", "ante",
+	"antes", "ants", "as", "asse", "assent", "asses", "assiez", "assions",
+)
+
+var frenchResidualSuffixes = newSuffixList("ion", "ier", "ière", "Ier", "Ière", "e", "ë")
+
+//
+// frenchWord is a French word with its regions.
+//
+type frenchWord struct {
+	w          []rune
+	rv, r1, r2 int
+}
+
+func stemFrench(w []rune) []rune {
+	w = frenchPrelude(w)
+	f := &frenchWord{w: w, rv: frenchRV(w)}
+	f.r1 = regionAfter(w, 0, frenchVowels)
+	f.r2 = regionAfter(w, f.r1, frenchVowels)
+
+	if f.standardSuffix() || f.iVerbSuffix() || f.verbSuffix() {
+		// Step 3: a final Y becomes i and a final ç becomes c.
+		if n := len(f.w); n > 0 {
+			switch f.w[n-1] {
+			case 'Y':
+				f.w[n-1] = 'i'
+			case 'ç':
+				f.w[n-1] = 'c'
+			}
+		}
+	} else {
+		f.residualSuffix()
+	}
+
+	f.undouble()
+	f.unaccent()
+	return frenchPostlude(f.w)
+}
+
+//
+// standardSuffix is step 1. Replacing amment or emment, or removing ment or
+// ments, does not count as a match, so that steps 2a and 2b still run.
+//
+func (f *frenchWord) standardSuffix() bool {
+	w := f.w
+	s, start := frenchStep1Suffixes.find(w, 0)
+	inR2 := start >= f.r2
+	switch s {
+	case "":
+		return false
+	case "ance", "iqUe", "isme", "able", "iste", "eux", "ances", "iqUes", "ismes", "ables", "istes":
+		if !inR2 {
+			return false
+		}
+		f.w = w[:start]
+	case "atrice", "ateur", "ation", "atrices", "ateurs", "ations":
+		if !inR2 {
+			return false
+		}
+		f.w = w[:start]
+		f.icSuffix()
+	case "logie", "logies":
+		if !inR2 {
+			return false
+		}
+		f.w = replaceSuffix(w, start, "log")
+	case "usion", "ution", "usions", "utions":
+		if !inR2 {
+			return false
+		}
+		f.w = replaceSuffix(w, start, "u")
+	case "ence", "ences":
+		if !inR2 {
+			return false
+		}
+		f.w = replaceSuffix(w, start, "ent")
+	case "ement", "ements":
+		if start < f.rv {
+			return false
+		}
+		f.w = w[:start]
+		switch s, start := newSuffixList("iv", "eus", "abl", "iqU", "ièr", "Ièr").find(f.w, 0); s {
+		case "iv":
+			if start >= f.r2 {
+				f.w = f.w[:start]
+				if start := suffixStart(f.w, "at"); start >= f.r2 {
+					f.w = f.w[:start]
+				}
+			}
+		case "eus":
+			if start >= f.r2 {
+				f.w = f.w[:start]
+			} else if start >= f.r1 {
+				f.w = replaceSuffix(f.w, start, "eux")
+			}
+		case "abl", "iqU":
+			if start >= f.r2 {
+				f.w = f.w[:start]
+			}
+		case "ièr", "Ièr":
+			if start >= f.rv {
+				f.w = replaceSuffix(f.w, start, "i")
+			}
+		}
+	case "ité", "ités":
+		if !inR2 {
+			return false
+		}
+		f.w = w[:start]
+		switch s, start := newSuffixList("abil", "ic", "iv").find(f.w, 0); s {
+		case "abil":
+			if start >= f.r2 {
+				f.w = f.w[:start]
+			} else {
+				f.w = replaceSuffix(f.w, start, "abl")
+			}
+		case "ic":
+			if start >= f.r2 {
+				f.w = f.w[:start]
+			} else {
+				f.w = replaceSuffix(f.w, start, "iqU")
+			}
+		case "iv":
+			if start >= f.r2 {
+				f.w = f.w[:start]
+			}
+		}
+	case "if", "ive", "ifs", "ives":
+		if !inR2 {
+			return false
+		}
+		f.w = w[:start]
+		if start := suffixStart(f.w, "at"); start >= f.r2 {
+			f.w = f.w[:start]
+			f.icSuffix()
+		}
+	case "eaux":
+		f.w = replaceSuffix(w, start, "eau")
+	case "aux":
+		if start < f.r1 {
+			return false
+		}
+		f.w = replaceSuffix(w, start, "al")
+	case "euse", "euses":
+		if inR2 {
+			f.w = w[:start]
+		} else if start >= f.r1 {
+			f.w = replaceSuffix(w, start, "eux")
+		} else {
+			return false
+		}
+	case "issement", "issements":
+		if start < f.r1 || start == 0 || inGroup(w, start-1, frenchVowels) {
+			return false
+		}
+		f.w = w[:start]
+	case "amment":
+		if start >= f.rv {
+			f.w = replaceSuffix(w, start, "ant")
+			return false
+		}
+		return false
+	case "emment":
+		if start >= f.rv {
+			f.w = replaceSuffix(w, start, "ent")
+			return false
+		}
+		return false
+	case "ment", "ments":
+		if start-1 >= f.rv && inGroup(w, start-1, frenchVowels) {
+			f.w = w[:start]
+			return false
+		}
+		return false
+	}
+	return true
+}
+
+//
+// icSuffix removes an ic in R2, or replaces it by iqU.
+//
+func (f *frenchWord) icSuffix() {
+	if start := suffixStart(f.w, "ic"); start >= f.r2 {
+		f.w = f.w[:start]
+	} else if start >= 0 {
+		f.w = replaceSuffix(f.w, start, "iqU")
+	}
+}
+
+//
+// iVerbSuffix is step 2a: the i-verb suffixes in RV, after a non-vowel in
+// RV.
+//
+func (f *frenchWord) iVerbSuffix() bool {
+	s, start := frenchIVerbSuffixes.find(f.w, f.rv)
+	if s == "" || start-1 < f.rv || inGroup(f.w, start-1, frenchVowels) {
+		return false
+	}
+	f.w = f.w[:start]
+	return true
+}
+
+//
+// verbSuffix is step 2b: the other verb suffixes in RV.
+//
+func (f *frenchWord) verbSuffix() bool {
+	switch s, start := frenchVerbSuffixes.find(f.w, f.rv); s {
+	case "":
+		return false
+	case "ions":
+		if start < f.r2 {
+			return false
+		}
+		f.w = f.w[:start]
+	case "âmes", "ât", "âtes", "a", "ai", "aIent", "ais", "ait", "ant", "ante",
+		"antes", "ants", "as", "asse", "assent", "asses", "assiez", "assions":
+		f.w = f.w[:start]
+		if start-1 >= f.rv && f.w[start-1] == 'e' {
+			f.w = f.w[:start-1]
+		}
+	default:
+		f.w = f.w[:start]
+	}
+	return true
+}
+
+//
+// residualSuffix is step 4.
+//
+func (f *frenchWord) residualSuffix() {
+	if n := len(f.w); n >= 2 && f.w[n-1] == 's' && !inGroup(f.w, n-2, "aiouès") {
+		f.w = f.w[:n-1]
+	}
+	switch s, start := frenchResidualSuffixes.find(f.w, f.rv); s {
+	case "ion":
+		if start >= f.r2 && start-1 >= f.rv && inGroup(f.w, start-1, "st") {
+			f.w = f.w[:start]
+		}
+	case "ier", "ière", "Ier", "Ière":
+		f.w = replaceSuffix(f.w, start, "i")
+	case "e":
+		f.w = f.w[:start]
+	case "ë":
+		if start-2 >= f.rv && endsWith(f.w[:start], "gu") {
+			f.w = f.w[:start]
+		}
+	}
+}
+
+//
+// undouble is step 5: enn, onn, ett, ell and eill lose their last letter.
+//
+func (f *frenchWord) undouble() {
+	for _, s := range []string{"enn", "onn", "ett", "ell", "eill"} {
+		if endsWith(f.w, s) {
+			f.w = f.w[:len(f.w)-1]
+			return
+		}
+	}
+}
+
+//
+// unaccent is step 6: an é or è followed by at least one non-vowel at the end
+// of the word becomes e.
+//
+func (f *frenchWord) unaccent() {
+	i := len(f.w) - 1
+	for i >= 0 && !inGroup(f.w, i, frenchVowels) {
+		i--
+	}
+	if i >= 0 && i < len(f.w)-1 && (f.w[i] == 'é' || f.w[i] == 'è') {
+		f.w[i] = 'e'
+	}
+}
+
+//
+// frenchRV returns the start of RV: after the third letter if the word starts
+// with two vowels or after par, col or tap, otherwise after the first vowel
+// not at the start of the word.
+//
+func frenchRV(w []rune) int {
+	if len(w) >= 3 && inGroup(w, 0, frenchVowels) && inGroup(w, 1, frenchVowels) {
+		return 3
+	}
+	for _, p := range []string{"par", "col", "tap"} {
+		if len(w) >= 3 && string(w[:3]) == p {
+			return 3
+		}
+	}
+	for i := 1; i < len(w); i++ {
+		if inGroup(w, i, frenchVowels) {
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+func frenchPrelude(w []rune) []rune {
+	for i, r := range w {
+		prev := inGroup(w, i-1, frenchVowels)
+		next := inGroup(w, i+1, frenchVowels)
+		switch {
+		case (r == 'u' || r == 'i') && prev && next:
+			w[i] -= 'a' - 'A'
+		case r == 'y' && (prev || next):
+			w[i] = 'Y'
+		case r == 'u' && i > 0 && w[i-1] == 'q':
+			w[i] = 'U'
+		}
+	}
+	return w
+}
+
+func frenchPostlude(w []rune) []rune {
+	for i, r := range w {
+		switch r {
+		case 'I':
+			w[i] = 'i'
+		case 'U':
+			w[i] = 'u'
+		case 'Y':
+			w[i] = 'y'
+		}
+	}
+	return w
+}
@@ -0,0 +1,132 @@
+package stemmer
+
+//
+// The German stemmer:
+//
+//    https://snowballstem.org/algorithms/german/stemmer.html
+//
+// ß is spelled ss, and a u or a y between vowels is kept as an upper case U
+// or Y while stemming, so that it counts as a consonant.
+//
+
+const germanVowels = "aeiouyäöü"
+
+var (
+	germanStep1Suffixes = newSuffixList("em", "ern", "er", "e", "en", "es", "s")
+	germanStep2Suffixes = newSuffixList("en", "er", "est", "st")
+	germanStep3Suffixes = newSuffixList("end", "ung", "ig", "ik", "isch", "lich", "heit", "keit")
+)
+
+func stemGerman(w []rune) []rune {
+	w = germanPrelude(w)
+	r1 := regionAfter(w, 0, germanVowels)
+	r2 := regionAfter(w, r1, germanVowels)
+	if r1 < 3 {
+		r1 = 3
+	}
+
+	// Step 1: em, ern, er, e, en, es and s after a valid s-ending, in R1; a
+	// niss left by e, en or es loses its last s.
+	switch s, start := germanStep1Suffixes.find(w, 0); s {
+	case "em", "ern", "er":
+		if start >= r1 {
+			w = w[:start]
+		}
+	case "e", "en", "es":
+		if start >= r1 {
+			w = w[:start]
+			if endsWith(w, "niss") {
+				w = w[:len(w)-1]
+			}
+		}
+	case "s":
+		if start >= r1 && inGroup(w, start-1, "bdfghklmnrt") {
+			w = w[:start]
+		}
+	}
+
+	// Step 2: en, er, est, and st after a valid st-ending itself after at
+	// least three letters, in R1.
+	switch s, start := germanStep2Suffixes.find(w, 0); s {
+	case "en", "er", "est":
+		if start >= r1 {
+			w = w[:start]
+		}
+	case "st":
+		if start >= r1 && start-1 >= 3 && inGroup(w, start-1, "bdfghklmnt") {
+			w = w[:start]
+		}
+	}
+
+	// Step 3: the derivational suffixes in R2.
+	switch s, start := germanStep3Suffixes.find(w, 0); s {
+	case "end", "ung":
+		if start >= r2 {
+			w = w[:start]
+			if s := suffixStart(w, "ig"); s >= r2 && !inGroup(w, s-1, "e") {
+				w = w[:s]
+			}
+		}
+	case "ig", "ik", "isch":
+		if start >= r2 && !inGroup(w, start-1, "e") {
+			w = w[:start]
+		}
+	case "lich", "heit":
+		if start >= r2 {
+			w = w[:start]
+			for _, s := range []string{"er", "en"} {
+				if start := suffixStart(w, s); start >= r1 {
+					w = w[:start]
+					break
+				}
+			}
+		}
+	case "keit":
+		if start >= r2 {
+			w = w[:start]
+			for _, s := range []string{"lich", "ig"} {
+				if start := suffixStart(w, s); start >= r2 {
+					w = w[:start]
+					break
+				}
+			}
+		}
+	}
+
+	return germanPostlude(w)
+}
+
+func germanPrelude(w []rune) []rune {
+	out := w[:0:0]
+	for _, r := range w {
+		if r == 'ß' {
+			out = append(out, 's', 's')
+		} else {
+			out = append(out, r)
+		}
+	}
+	for i := 1; i < len(out)-1; i++ {
+		if (out[i] == 'u' || out[i] == 'y') && inGroup(out, i-1, germanVowels) && inGroup(out, i+1, germanVowels) {
+			out[i] -= 'a' - 'A'
+		}
+	}
+	return out
+}
+
+func germanPostlude(w []rune) []rune {
+	for i, r := range w {
+		switch r {
+		case 'U':
+			w[i] = 'u'
+		case 'Y':
+			w[i] = 'y'
+		case 'ä':
+			w[i] = 'a'
+		case 'ö':
+			w[i] = 'o'
+		case 'ü':
+			w[i] = 'u'
+		}
+	}
+	return w
+}
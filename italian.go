@@ -0,0 +1,176 @@
+package stemmer
+
+import "strings"
+
+//
+// The Italian stemmer:
+//
+//    https://snowballstem.org/algorithms/italian/stemmer.html
+//
+// Acute accents become grave accents, and the u of qu and a u or an i between
+// vowels are kept as an upper case U or I while stemming, so that they count
+// as consonants.
+//
+
+const italianVowels = "aeiouàèìòù"
+
+var (
+	italianPrelude = strings.NewReplacer("á", "à", "é", "è", "í", "ì", "ó", "ò", "ú", "ù", "qu", "qU")
+
+	italianPronouns = newSuffixList(
+		"ci", "gli", "la", "le", "li", "lo", "mi", "ne", "si", "ti", "vi",
+		"sene", "gliela", "gliele", "glieli", "glielo", "gliene", "mela",
+		"mele", "meli", "melo", "mene", "tela", "tele", "teli", "telo", "tene",
+		"cela", "cele", "celi", "celo", "cene", "vela", "vele", "veli", "velo",
+		"vene",
+	)
+	italianPronounEndings = newSuffixList("ando", "endo", "ar", "er", "ir")
+	italianStep1Suffixes  = newSuffixList(
+		"anza", "anze", "ico", "ici", "ica", "ice", "iche", "ichi", "ismo",
+		"ismi", "abile", "abili", "ibile", "ibili", "ista", "iste", "isti",
+		"istà", "istè", "istì", "oso", "osi", "osa", "ose", "mente", "atrice",
+		"atrici", "ante", "anti",
+		"azione", "azioni", "atore", "atori",
+		"logia", "logie",
+		"uzione", "uzioni", "usione", "usioni",
+		"enza", "enze",
+		"amento", "amenti", "imento", "imenti",
+		"amente",
+		"ità",
+		"ivo", "ivi", "iva", "ive",
+	)
+	italianVerbSuffixes = newSuffixList(
+		"ammo", "ando", "ano", "are", "arono", "asse", "assero", "assi",
+		"assimo", "ata", "ate", "ati", "ato", "ava", "avamo", "avano", "avate",
+		"avi", "avo", "emmo", "enda", "ende", "endi", "endo", "erà", "erai",
+		"eranno", "ere", "erebbe", "erebbero", "erei", "eremmo", "eremo",
+		"ereste", "eresti", "erete", "erò", "erono", "essero", "ete", "eva",
+		"evamo", "evano", "evate", "evi", "evo", "iamo", "immo", "irà",
+		"irai", "iranno", "ire", "irebbe", "irebbero", "irei", "iremmo",
+		"iremo", "ireste", "iresti", "irete", "irò", "irono", "isca", "iscano",
+		"isce", "isci", "isco", "iscono", "issero", "ita", "ite", "iti", "ito",
+		"iva", "ivamo", "ivano", "ivate", "ivi", "ivo", "ono", "uta", "ute",
+		"uti", "uto", "ar", "ir",
+	)
+)
+
+func stemItalian(w []rune) []rune {
+	w = []rune(italianPrelude.Replace(string(w)))
+	for i, r := range w {
+		if (r == 'u' || r == 'i') && inGroup(w, i-1, italianVowels) && inGroup(w, i+1, italianVowels) {
+			w[i] -= 'a' - 'A'
+		}
+	}
+	rv := romanceRV(w, italianVowels)
+	r1 := regionAfter(w, 0, italianVowels)
+	r2 := regionAfter(w, r1, italianVowels)
+
+	// Step 0: an attached pronoun after a gerund in RV is removed, and after
+	// an infinitive in RV replaced by e.
+	if s, start := italianPronouns.find(w, 0); s != "" {
+		switch verb, vstart := italianPronounEndings.find(w[:start], 0); {
+		case verb == "" || vstart < rv:
+		case verb == "ando" || verb == "endo":
+			w = w[:start]
+		default:
+			w = replaceSuffix(w, start, "e")
+		}
+	}
+
+	// Step 1 removes the standard suffixes, and only if it does not, step 2
+	// the verb suffixes in RV.
+	var ok bool
+	if w, ok = italianStandardSuffix(w, rv, r1, r2); !ok {
+		if s, start := italianVerbSuffixes.find(w, rv); s != "" {
+			w = w[:start]
+		}
+	}
+
+	// Step 3: a final a, e, i, o, à, è, ì or ò in RV is removed, and then an
+	// i in RV before it; an h after c or g in RV is removed.
+	if n := len(w); n-1 >= rv && inGroup(w, n-1, "aeioàèìò") {
+		w = w[:n-1]
+		if n := len(w); n-1 >= rv && w[n-1] == 'i' {
+			w = w[:n-1]
+		}
+	}
+	if n := len(w); n-2 >= rv && w[n-1] == 'h' && inGroup(w, n-2, "cg") {
+		w = w[:n-1]
+	}
+
+	for i, r := range w {
+		switch r {
+		case 'I':
+			w[i] = 'i'
+		case 'U':
+			w[i] = 'u'
+		}
+	}
+	return w
+}
+
+//
+// italianStandardSuffix is step 1, and reports whether it changed w.
+//
+func italianStandardSuffix(w []rune, rv, r1, r2 int) ([]rune, bool) {
+	s, start := italianStep1Suffixes.find(w, 0)
+	switch s {
+	case "":
+		return w, false
+	case "amento", "amenti", "imento", "imenti":
+		if start < rv {
+			return w, false
+		}
+	case "amente":
+		if start < r1 {
+			return w, false
+		}
+	default:
+		if start < r2 {
+			return w, false
+		}
+	}
+
+	// removeInR2 removes each of the suffixes in turn from the end of w
+	// while it is in R2.
+	removeInR2 := func(suffixes ...string) {
+		for _, s := range suffixes {
+			start := suffixStart(w, s)
+			if start < r2 {
+				return
+			}
+			w = w[:start]
+		}
+	}
+
+	switch s {
+	case "azione", "azioni", "atore", "atori":
+		w = w[:start]
+		removeInR2("ic")
+	case "logia", "logie":
+		w = replaceSuffix(w, start, "log")
+	case "uzione", "uzioni", "usione", "usioni":
+		w = replaceSuffix(w, start, "u")
+	case "enza", "enze":
+		w = replaceSuffix(w, start, "ente")
+	case "amente":
+		w = w[:start]
+		switch s, _ := newSuffixList("iv", "os", "ic", "abil").find(w, 0); s {
+		case "iv":
+			removeInR2("iv", "at")
+		case "os", "ic", "abil":
+			removeInR2(s)
+		}
+	case "ità":
+		w = w[:start]
+		if s, _ := newSuffixList("abil", "ic", "iv").find(w, 0); s != "" {
+			removeInR2(s)
+		}
+	case "ivo", "ivi", "iva", "ive":
+		w = w[:start]
+		removeInR2("at", "ic")
+	default:
+		w = w[:start]
+	}
+	return w, true
+}
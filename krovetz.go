@@ -0,0 +1,245 @@
+package stemmer
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+//
+// Krovetz is a dictionary stemmer in the style of R. Krovetz, "Viewing
+// morphology as an inference process", SIGIR 1993: an inflectional ending is
+// only removed if what is left is a word of the dictionary, so that every stem
+// is a word ("abbreviated" stems to "abbreviate" where Stem gives "abbrevi").
+//
+// A word of the dictionary is its own stem: "news" is not "new". Otherwise
+// the rule of the longest ending of the word decides. Its candidates are
+// tried in order and the first one found in the dictionary is the stem; if
+// there is none, the word is its own stem:
+//
+//    -ies   -y, -ie              ponies    ->  pony
+//    -ied   -y, -ie              studied   ->  study
+//    -eed   -ee                  agreed    ->  agree
+//    -ying  -ie, -y              dying     ->  die
+//    -ing   undoubled, -e, -     running   ->  run
+//                                making    ->  make
+//    -ed    undoubled, -e, -     stopped   ->  stop
+//                                hoped     ->  hope
+//    -es    -e, -                horses    ->  horse
+//                                boxes     ->  box
+//    -s     -                    cats      ->  cat
+//    -ss, -us, -is               left alone
+//
+// A candidate must have three letters or more, and the part of the word it
+// keeps a vowel, unless it ends in -ie: "thing" is not "the". Only a double
+// consonant other than l, s or z is undoubled: "falling" is "fall". The
+// irregular forms of krovetzIrregulars are stemmed as a whole.
+//
+// The default lexicon, rules/krovetz.txt, holds 17784 headwords: the words
+// of Webster's Second International (web2) that occur in a corpus of English
+// books, or whose inflected forms do, less those that are inflected forms of
+// another headword, such as "dogs", unless they are words of their own, such
+// as "news" or "species".
+//
+
+//go:embed rules/krovetz.txt
+var krovetzLexicon string
+
+var krovetzRules = []struct {
+	suffix   string
+	endings  []string
+	undouble bool
+}{
+	{"ying", []string{"ie", "y"}, false},
+	{"ies", []string{"y", "ie"}, false},
+	{"ied", []string{"y", "ie"}, false},
+	{"eed", []string{"ee"}, false},
+	{"ing", []string{"e", ""}, true},
+	{"ed", []string{"e", ""}, true},
+	{"es", []string{"e", ""}, false},
+	{"ss", nil, false},
+	{"us", nil, false},
+	{"is", nil, false},
+	{"s", []string{""}, false},
+}
+
+//
+// Irregular forms, which the rules cannot stem.
+//
+var krovetzIrregulars = map[string]string{
+	"being":    "be",
+	"doing":    "do",
+	"does":     "do",
+	"going":    "go",
+	"goes":     "go",
+	"children": "child",
+	"men":      "man",
+	"women":    "woman",
+	"feet":     "foot",
+	"teeth":    "tooth",
+	"mice":     "mouse",
+	"geese":    "goose",
+	"oxen":     "ox",
+	"lice":     "louse",
+}
+
+//
+// Krovetz is a Stemmer backed by a dictionary. The zero Krovetz uses the
+// embedded default lexicon, rules/krovetz.txt. A Krovetz can be used by
+// several goroutines, but not while words are added to it.
+//
+type Krovetz struct {
+	words map[string]bool
+}
+
+var (
+	krovetzOnce    sync.Once
+	krovetzDefault map[string]bool
+)
+
+func defaultKrovetzWords() map[string]bool {
+	krovetzOnce.Do(func() {
+		k, err := ReadKrovetz(strings.NewReader(krovetzLexicon))
+		if err != nil {
+			panic(err)
+		}
+		krovetzDefault = k.words
+	})
+	return krovetzDefault
+}
+
+//
+// NewKrovetz returns a Krovetz whose dictionary holds words.
+//
+func NewKrovetz(words ...string) *Krovetz {
+	k := &Krovetz{words: make(map[string]bool)}
+	k.Add(words...)
+	return k
+}
+
+//
+// LoadKrovetz returns a Krovetz whose dictionary is the word list in the file
+// at path, see ReadKrovetz.
+//
+func LoadKrovetz(path string) (*Krovetz, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadKrovetz(f)
+}
+
+//
+// ReadKrovetz returns a Krovetz whose dictionary is the word list read from r,
+// with one word per line like rules/krovetz.txt. Blank lines are skipped.
+//
+func ReadKrovetz(r io.Reader) (*Krovetz, error) {
+	k := NewKrovetz()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" {
+			k.Add(word)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+//
+// Add adds words to the dictionary of k. Adding words to the zero Krovetz
+// adds them to a copy of the default lexicon.
+//
+func (k *Krovetz) Add(words ...string) {
+	if k.words == nil {
+		k.words = make(map[string]bool)
+		for w := range defaultKrovetzWords() {
+			k.words[w] = true
+		}
+	}
+	for _, w := range words {
+		k.words[string(appendLowerString(nil, w))] = true
+	}
+}
+
+//
+// Contains reports whether word is in the dictionary of k.
+//
+func (k *Krovetz) Contains(word string) bool {
+	return k.dictionary()[string(appendLowerString(nil, strings.TrimSpace(word)))]
+}
+
+func (k *Krovetz) dictionary() map[string]bool {
+	if k.words == nil {
+		return defaultKrovetzWords()
+	}
+	return k.words
+}
+
+func (k *Krovetz) Stem(word []byte) []byte {
+	w := bytes.TrimSpace(appendLower(make([]byte, 0, len(word)), word))
+	if stem, ok := k.stem(string(w)); ok {
+		return append(w[:0], stem...)
+	}
+	return w
+}
+
+func (k *Krovetz) StemString(word string) string {
+	return stringResult(word, k.Stem([]byte(word)))
+}
+
+func (*Krovetz) Name() string { return "krovetz" }
+
+//
+// stem returns the stem of the lower case word, if it is not word itself.
+//
+func (k *Krovetz) stem(word string) (string, bool) {
+	if stem, ok := krovetzIrregulars[word]; ok {
+		return stem, true
+	}
+	words := k.dictionary()
+	if words[word] {
+		return "", false
+	}
+	for _, rule := range krovetzRules {
+		if !strings.HasSuffix(word, rule.suffix) {
+			continue
+		}
+		base := word[:len(word)-len(rule.suffix)]
+		if n := len(base); rule.undouble && n >= 2 && base[n-1] == base[n-2] && strings.IndexByte("aeioulsz", base[n-1]) < 0 {
+			if c := base[:n-1]; krovetzCandidate(c, "") && words[c] {
+				return c, true
+			}
+		}
+		for _, ending := range rule.endings {
+			if c := base + ending; krovetzCandidate(base, ending) && words[c] {
+				return c, true
+			}
+		}
+		return "", false
+	}
+	return "", false
+}
+
+func krovetzCandidate(base, ending string) bool {
+	if len(base)+len(ending) < 3 {
+		return false
+	}
+	if ending == "ie" {
+		return true
+	}
+	return strings.ContainsAny(base, "aeiouy")
+}
+
+//
+// StemKrovetz returns the stem of word with the default lexicon.
+//
+func StemKrovetz(word []byte) []byte {
+	return (&Krovetz{}).Stem(word)
+}
@@ -0,0 +1,159 @@
+package stemmer
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestKrovetz(t *testing.T) {
+	k, err := LoadKrovetz("testdata/krovetz/dictionary.txt")
+	if err != nil {
+		t.Fatalf("LoadKrovetz() returned an error: '%v'", err)
+	}
+
+	fixtures := []word{
+		[]byte("abbreviated"),
+		[]byte("Ponies"),
+		[]byte("studied"),
+		[]byte("agreed"),
+		[]byte("feed"),
+		[]byte("dying"),
+		[]byte("ties"),
+		[]byte("running"),
+		[]byte("falling"),
+		[]byte("making"),
+		[]byte("thing"),
+		[]byte("stopped"),
+		[]byte("hoped"),
+		[]byte("horses"),
+		[]byte("boxes"),
+		[]byte("glasses"),
+		[]byte("cats"),
+		[]byte("dogs"),
+		[]byte("children"),
+	}
+
+	stemmed := []word{
+		[]byte("abbreviate"),
+		[]byte("pony"),
+		[]byte("study"),
+		[]byte("agree"),
+		[]byte("feed"),
+		[]byte("die"),
+		[]byte("tie"),
+		[]byte("run"),
+		[]byte("fall"),
+		[]byte("make"),
+		[]byte("thing"),
+		[]byte("stop"),
+		[]byte("hope"),
+		[]byte("horse"),
+		[]byte("box"),
+		[]byte("glass"),
+		[]byte("cat"),
+		[]byte("dogs"),
+		[]byte("child"),
+	}
+
+	for i, value := range fixtures {
+		if result := k.Stem(value); !bytes.Equal(result, stemmed[i]) {
+			t.Errorf("Krovetz.Stem() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, stemmed[i])
+		}
+	}
+}
+
+func TestKrovetzDefault(t *testing.T) {
+	fixtures := []string{
+		"abbreviated",
+		"ponies",
+		"news",
+		"species",
+		"studied",
+		"running",
+		"dogs",
+		"glasses",
+		"formed",
+		"during",
+		"children",
+	}
+
+	stemmed := []string{
+		"abbreviate",
+		"pony",
+		"news",
+		"species",
+		"study",
+		"run",
+		"dog",
+		"glass",
+		"form",
+		"during",
+		"child",
+	}
+
+	for i, value := range fixtures {
+		if result := StemKrovetz([]byte(value)); string(result) != stemmed[i] {
+			t.Errorf("StemKrovetz() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, stemmed[i])
+		}
+	}
+}
+
+func TestKrovetzAdd(t *testing.T) {
+	k := &Krovetz{}
+	if k.Contains("blog") || !k.Contains("Cat") {
+		t.Errorf("Krovetz.Contains() does not use the default lexicon")
+	}
+	if result := k.StemString("blogged"); result != "blogged" {
+		t.Errorf("Krovetz.StemString() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", "blogged", result, "blogged")
+	}
+
+	k.Add("Blog")
+	if result := k.StemString("blogged"); result != "blog" {
+		t.Errorf("Krovetz.StemString() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", "blogged", result, "blog")
+	}
+	if result := k.StemString("cats"); result != "cat" {
+		t.Errorf("Krovetz.StemString() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", "cats", result, "cat")
+	}
+	if (&Krovetz{}).Contains("blog") {
+		t.Errorf("Krovetz.Add() changed the default lexicon")
+	}
+}
+
+//
+// testdata/krovetz/output.txt holds the stems of voc.txt with the default
+// lexicon.
+//
+func TestKrovetzVocal(t *testing.T) {
+	v, err := os.Open("voc.txt")
+	if err != nil {
+		panic(err)
+	}
+	defer v.Close()
+	vocScanner := bufio.NewScanner(v)
+
+	o, err := os.Open("testdata/krovetz/output.txt")
+	if err != nil {
+		panic(err)
+	}
+	defer o.Close()
+	outScanner := bufio.NewScanner(o)
+
+	for vocScanner.Scan() {
+		outScanner.Scan()
+		word := vocScanner.Bytes()
+		stem := outScanner.Bytes()
+
+		if result := StemKrovetz(word); !bytes.Equal(result, stem) {
+			t.Errorf("StemKrovetz() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", word, result, stem)
+		}
+	}
+}
+
+func BenchmarkStemKrovetz(b *testing.B) {
+	word := []byte("troubles")
+	for n := 0; n < b.N; n++ {
+		StemKrovetz(word)
+	}
+}
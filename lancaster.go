@@ -0,0 +1,301 @@
+package stemmer
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//
+// Lancaster is the iterative stemmer of C. D. Paice and G. Husk, "Another
+// stemmer", SIGIR Forum 24(3), 1990, also known as the Lancaster stemmer.
+// It conflates much more than Porter does.
+//
+// Its rules are data. A rule file has one rule per line, written the way the
+// paper writes them: the ending spelled backwards, an optional * when the rule
+// only applies to intact words, the number of letters to remove, an optional
+// string to append, and . to stop or > to go on stemming the result:
+//
+//    ai*2.                 removes the -ia of intact words and stops
+//    sei3y>                replaces -ies by -y and goes on
+//    ss0.                  protects -ss: the word is its own stem
+//
+// Everything on a line after the rule, or after a #, is a comment, and the
+// rule end0. ends the file. rules/lancaster.txt is the standard rule file.
+//
+// At each iteration the first rule in the file whose ending matches the word
+// and whose result is acceptable applies: a result starting with a vowel must
+// keep 2 letters, any other 3 letters, one of them a vowel or y. Stemming
+// stops when no rule applies.
+//
+// A rule that goes on stemming must not leave the word as it was, or make it
+// longer. Rules that replace letters by as many others, like cn1t>, may still
+// lead to one another; stemming stops after as many of them in a row as there
+// are rules.
+//
+
+//go:embed rules/lancaster.txt
+var lancasterFile string
+
+var lancasterStandard = mustReadLancaster("rules/lancaster.txt", strings.NewReader(lancasterFile))
+
+//
+// LancasterRule is a rule of a Lancaster stemmer.
+//
+type LancasterRule struct {
+	// Ending is the ending the rule applies to, spelled forwards.
+	Ending string
+	// Intact restricts the rule to words no rule has changed yet.
+	Intact bool
+	// Remove is the number of letters removed from the end of the word, and
+	// Append the string appended then.
+	Remove int
+	Append string
+	// Continue goes on stemming the result; otherwise it is the stem.
+	Continue bool
+}
+
+//
+// Lancaster is a Stemmer running a table of Lancaster rules.
+//
+type Lancaster struct {
+	rules []LancasterRule
+	// index are the rules by the last letter of their ending, in the order of
+	// rules.
+	index map[byte][]LancasterRule
+}
+
+//
+// NewLancaster returns a Lancaster stemmer running rules, in order.
+//
+func NewLancaster(rules []LancasterRule) (*Lancaster, error) {
+	l := &Lancaster{index: make(map[byte][]LancasterRule)}
+	for _, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return nil, err
+		}
+		last := rule.Ending[len(rule.Ending)-1]
+		l.index[last] = append(l.index[last], rule)
+	}
+	l.rules = append([]LancasterRule(nil), rules...)
+	return l, nil
+}
+
+func (rule LancasterRule) validate() error {
+	if rule.Ending == "" {
+		return fmt.Errorf("rule without an ending")
+	}
+	for _, s := range []string{rule.Ending, rule.Append} {
+		for i := 0; i < len(s); i++ {
+			if s[i] < 'a' || s[i] > 'z' {
+				return fmt.Errorf("rule %s: %q is not a lower case letter", rule, s[i])
+			}
+		}
+	}
+	if rule.Remove < 0 || rule.Remove > len(rule.Ending) {
+		return fmt.Errorf("rule %s: removes %d letters of a %d letter ending", rule, rule.Remove, len(rule.Ending))
+	}
+	if rule.Continue && rule.Remove < len(rule.Append) {
+		return fmt.Errorf("rule %s: goes on stemming a longer word", rule)
+	}
+	if rule.Continue && rule.Ending[len(rule.Ending)-rule.Remove:] == rule.Append {
+		return fmt.Errorf("rule %s: goes on stemming the same word", rule)
+	}
+	return nil
+}
+
+//
+// String returns the rule the way a rule file writes it.
+//
+func (rule LancasterRule) String() string {
+	var b strings.Builder
+	for i := len(rule.Ending) - 1; i >= 0; i-- {
+		b.WriteByte(rule.Ending[i])
+	}
+	if rule.Intact {
+		b.WriteByte('*')
+	}
+	b.WriteString(strconv.Itoa(rule.Remove))
+	b.WriteString(rule.Append)
+	if rule.Continue {
+		b.WriteByte('>')
+	} else {
+		b.WriteByte('.')
+	}
+	return b.String()
+}
+
+//
+// ParseLancasterRule parses a rule written the way a rule file writes it.
+//
+func ParseLancasterRule(s string) (LancasterRule, error) {
+	var rule LancasterRule
+	i := 0
+	for i < len(s) && 'a' <= s[i] && s[i] <= 'z' {
+		i++
+	}
+	ending := make([]byte, i)
+	for k := range ending {
+		ending[k] = s[i-1-k]
+	}
+	rule.Ending = string(ending)
+	if i < len(s) && s[i] == '*' {
+		rule.Intact = true
+		i++
+	}
+	start := i
+	for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+		i++
+	}
+	if start == 0 || i == start {
+		return rule, fmt.Errorf("rule %q: want an ending and a number of letters to remove", s)
+	}
+	rule.Remove, _ = strconv.Atoi(s[start:i])
+	start = i
+	for i < len(s) && 'a' <= s[i] && s[i] <= 'z' {
+		i++
+	}
+	rule.Append = s[start:i]
+	if i != len(s)-1 || s[i] != '.' && s[i] != '>' {
+		return rule, fmt.Errorf("rule %q: want a . or a > at the end", s)
+	}
+	rule.Continue = s[i] == '>'
+	return rule, rule.validate()
+}
+
+//
+// LoadLancaster reads the rule file at path and returns the Lancaster stemmer
+// running its rules.
+//
+func LoadLancaster(path string) (*Lancaster, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadLancaster(path, f)
+}
+
+//
+// ReadLancaster is LoadLancaster for a rule file read from r. file names it
+// in errors, which are *RuleFileError for the problems of the rules.
+//
+func ReadLancaster(file string, r io.Reader) (*Lancaster, error) {
+	var rules []LancasterRule
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "end0." {
+			break
+		}
+		rule, err := ParseLancasterRule(fields[0])
+		if err != nil {
+			return nil, &RuleFileError{File: file, Line: n, Err: err}
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewLancaster(rules)
+}
+
+func mustReadLancaster(file string, r io.Reader) *Lancaster {
+	l, err := ReadLancaster(file, r)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+//
+// Rules returns the rules of l, in order.
+//
+func (l *Lancaster) Rules() []LancasterRule {
+	return append([]LancasterRule(nil), l.rules...)
+}
+
+func (l *Lancaster) Stem(word []byte) []byte {
+	return l.stem(bytes.TrimSpace(appendLower(make([]byte, 0, len(word)), word)))
+}
+
+func (l *Lancaster) StemString(word string) string {
+	return stringResult(word, l.Stem([]byte(word)))
+}
+
+func (*Lancaster) Name() string { return "lancaster" }
+
+func (l *Lancaster) stem(word []byte) []byte {
+	intact := true
+	// same counts the rules in a row that kept the length of the word.
+	same := 0
+next:
+	for len(word) > 0 {
+		for _, rule := range l.index[word[len(word)-1]] {
+			if rule.Intact && !intact || len(word) < len(rule.Ending) ||
+				string(word[len(word)-len(rule.Ending):]) != rule.Ending {
+				continue
+			}
+			stem := word[:len(word)-rule.Remove]
+			if !lancasterAcceptable(stem, rule.Append) {
+				continue
+			}
+			if rule.Remove == 0 && rule.Append == "" {
+				return word
+			}
+			word = append(stem, rule.Append...)
+			intact = false
+			if !rule.Continue {
+				return word
+			}
+			if rule.Remove > len(rule.Append) {
+				same = 0
+			} else if same++; same > len(l.rules) {
+				return word
+			}
+			continue next
+		}
+		break
+	}
+	return word
+}
+
+//
+// lancasterAcceptable reports whether stem followed by suffix is an
+// acceptable result: 2 letters if it starts with a vowel, otherwise 3 letters
+// with a vowel or y among them.
+//
+func lancasterAcceptable(stem []byte, suffix string) bool {
+	n := len(stem) + len(suffix)
+	if n == 0 {
+		return false
+	}
+	first := suffix
+	if len(stem) > 0 {
+		first = string(stem[:1])
+	}
+	if strings.IndexByte("aeiou", first[0]) >= 0 {
+		return n >= 2
+	}
+	return n >= 3 && (strings.ContainsAny(string(stem), "aeiouy") || strings.ContainsAny(suffix, "aeiouy"))
+}
+
+//
+// StemLancaster returns the stem of word according to the standard rules of
+// the Lancaster algorithm.
+//
+func StemLancaster(word []byte) []byte {
+	return lancasterStandard.Stem(word)
+}
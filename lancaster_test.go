@@ -0,0 +1,188 @@
+package stemmer
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseLancasterRule(t *testing.T) {
+	fixtures := []string{
+		"ai*2.",
+		"sei3y>",
+		"ss0.",
+		"hsiug5ct.",
+	}
+
+	rules := []LancasterRule{
+		{Ending: "ia", Intact: true, Remove: 2},
+		{Ending: "ies", Remove: 3, Append: "y", Continue: true},
+		{Ending: "ss"},
+		{Ending: "guish", Remove: 5, Append: "ct"},
+	}
+
+	for k, value := range fixtures {
+		result, err := ParseLancasterRule(value)
+		if err != nil || result != rules[k] {
+			t.Errorf("ParseLancasterRule() return value not what was expected, pass: '%s' return: '%+v' '%v' expected: '%+v'", value, result, err, rules[k])
+		}
+		if s := result.String(); s != value {
+			t.Errorf("LancasterRule.String() return value not what was expected, return: '%s' expected: '%s'", s, value)
+		}
+	}
+}
+
+func TestParseLancasterRuleError(t *testing.T) {
+	fixtures := []string{
+		"",
+		"ai2",
+		"ai*.",
+		"3y>",
+		"ai5.",
+		"sei3Y>",
+		"sei3y>>",
+		"s1s>",
+	}
+
+	for _, value := range fixtures {
+		if _, err := ParseLancasterRule(value); err == nil {
+			t.Errorf("ParseLancasterRule() did not return an error, pass: '%s'", value)
+		}
+	}
+}
+
+func TestNewLancasterError(t *testing.T) {
+	fixtures := []LancasterRule{
+		{Ending: "s", Remove: 1, Append: "s", Continue: true},
+		{Ending: "ss", Continue: true},
+		{Ending: "s", Remove: 1, Append: "es", Continue: true},
+	}
+
+	for _, value := range fixtures {
+		if _, err := NewLancaster([]LancasterRule{value}); err == nil {
+			t.Errorf("NewLancaster() did not return an error, pass: '%s'", value)
+		}
+	}
+
+	_, err := ReadLancaster("loop.txt", strings.NewReader("ss0.\ns1s>\n"))
+	if e, ok := err.(*RuleFileError); !ok || e.Line != 2 {
+		t.Errorf("ReadLancaster() error not what was expected, return: '%v'", err)
+	}
+}
+
+//
+// Rules keeping the length of the word may lead to one another for ever; the
+// stemmer gives up after as many of them as there are rules.
+//
+func TestLancasterLoop(t *testing.T) {
+	l, err := ReadLancaster("loop.txt", strings.NewReader("y1i>\ni1y>\n"))
+	if err != nil {
+		t.Fatalf("ReadLancaster() returned an error: '%v'", err)
+	}
+	if result := l.StemString("cry"); result != "cri" {
+		t.Errorf("Lancaster.StemString() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", "cry", result, "cri")
+	}
+}
+
+func TestStemLancaster(t *testing.T) {
+	fixtures := []word{
+		// The examples of NLTK's LancasterStemmer.
+		[]byte("maximum"),
+		[]byte("presumably"),
+		[]byte("multiply"),
+		[]byte("provision"),
+		[]byte("owed"),
+		[]byte("ear"),
+		[]byte("saying"),
+		[]byte("crying"),
+		[]byte("string"),
+		[]byte("meant"),
+		[]byte("cement"),
+		[]byte("Happiness"),
+	}
+
+	stemmed := []word{
+		[]byte("maxim"),
+		[]byte("presum"),
+		[]byte("multiply"),
+		[]byte("provid"),
+		[]byte("ow"),
+		[]byte("ear"),
+		[]byte("say"),
+		[]byte("cry"),
+		[]byte("string"),
+		[]byte("meant"),
+		[]byte("cem"),
+		[]byte("happy"),
+	}
+
+	for k, value := range fixtures {
+		if result := StemLancaster(value); !bytes.Equal(result, stemmed[k]) {
+			t.Errorf("StemLancaster() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, stemmed[k])
+		}
+	}
+}
+
+func TestReadLancaster(t *testing.T) {
+	l, err := ReadLancaster("custom.txt", strings.NewReader("# Plurals only.\nsei3y.   -ies > -y\nss0.\ns1.\nend0.\nnot a rule\n"))
+	if err != nil {
+		t.Fatalf("ReadLancaster() returned an error: '%v'", err)
+	}
+	if n := len(l.Rules()); n != 3 {
+		t.Errorf("Lancaster.Rules() return value not what was expected, return: '%d' expected: '%d'", n, 3)
+	}
+
+	fixtures := []string{"ponies", "caress", "cats", "running"}
+	stemmed := []string{"pony", "caress", "cat", "running"}
+
+	for k, value := range fixtures {
+		if result := l.StemString(value); result != stemmed[k] {
+			t.Errorf("Lancaster.StemString() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, stemmed[k])
+		}
+	}
+
+	_, err = ReadLancaster("custom.txt", strings.NewReader("sei3y.\n\nss0\n"))
+	if e, ok := err.(*RuleFileError); !ok || e.Line != 3 {
+		t.Errorf("ReadLancaster() error not what was expected, return: '%v'", err)
+	}
+}
+
+//
+// testdata/lancaster/output.txt holds the stems StemLancaster gives for
+// voc.txt with the standard rules, a snapshot guarding against regressions;
+// only the examples above come from another implementation.
+//
+func TestLancasterVocal(t *testing.T) {
+	v, err := os.Open("voc.txt")
+	if err != nil {
+		panic(err)
+	}
+	defer v.Close()
+	vocScanner := bufio.NewScanner(v)
+
+	o, err := os.Open("testdata/lancaster/output.txt")
+	if err != nil {
+		panic(err)
+	}
+	defer o.Close()
+	outScanner := bufio.NewScanner(o)
+
+	for vocScanner.Scan() {
+		outScanner.Scan()
+		word := vocScanner.Bytes()
+		stem := outScanner.Bytes()
+
+		if result := StemLancaster(word); !bytes.Equal(result, stem) {
+			t.Errorf("StemLancaster() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", word, result, stem)
+		}
+	}
+}
+
+func BenchmarkStemLancaster(b *testing.B) {
+	word := []byte("troubles")
+	for n := 0; n < b.N; n++ {
+		StemLancaster(word)
+	}
+}
@@ -0,0 +1,173 @@
+package stemmer
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+//
+// The stemmers of the other languages are ports of the Snowball algorithms
+// published at https://snowballstem.org/algorithms/. They work on runes, as
+// Snowball works on characters, and are registered under the ISO 639-1 code
+// of their language.
+//
+// The comments of the Snowball definitions use the regions of the word:
+//
+//    R1                    the part after the first non-vowel following a
+//                          vowel, or the end of the word
+//    R2                    the part of R1 after the first non-vowel following
+//                          a vowel in R1, or the end of the word
+//    RV                    a region defined by each language, usually after
+//                          the first vowel
+//
+// and a suffix is "in" a region when it starts at or after the start of the
+// region.
+//
+
+var languages = []languageStemmer{
+	{"da", stemDanish},
+	{"de", stemGerman},
+	{"es", stemSpanish},
+	{"fi", stemFinnish},
+	{"fr", stemFrench},
+	{"it", stemItalian},
+	{"nl", stemDutch},
+	{"no", stemNorwegian},
+	{"pt", stemPortuguese},
+	{"ru", stemRussian},
+	{"sv", stemSwedish},
+}
+
+//
+// languageStemmer is the Stemmer of a language, registered under its code.
+//
+type languageStemmer struct {
+	code string
+	stem func(word []rune) []rune
+}
+
+func (l languageStemmer) Stem(word []byte) []byte {
+	return stemRunes(word, l.stem)
+}
+
+func (l languageStemmer) StemString(word string) string {
+	return stringResult(word, stemRunes([]byte(word), l.stem))
+}
+
+func (l languageStemmer) Name() string { return l.code }
+
+//
+// stemRunes lower cases and trims word and stems its runes with stem.
+//
+func stemRunes(word []byte, stem func(word []rune) []rune) []byte {
+	w := bytes.TrimSpace(appendLower(make([]byte, 0, len(word)), word))
+	return []byte(string(stem([]rune(string(w)))))
+}
+
+//
+// suffixList is a list of suffixes, longest first.
+//
+type suffixList []string
+
+func newSuffixList(suffixes ...string) suffixList {
+	l := suffixList(suffixes)
+	sort.SliceStable(l, func(i, j int) bool {
+		return utf8.RuneCountInString(l[i]) > utf8.RuneCountInString(l[j])
+	})
+	return l
+}
+
+//
+// find returns the longest suffix of w in l that starts at or after limit,
+// and where it starts, or "" and -1 if there is none.
+//
+func (l suffixList) find(w []rune, limit int) (string, int) {
+	for _, s := range l {
+		if start := suffixStart(w, s); start >= limit {
+			return s, start
+		}
+	}
+	return "", -1
+}
+
+//
+// suffixStart returns where s starts if w ends with s, or -1.
+//
+func suffixStart(w []rune, s string) int {
+	i := len(w)
+	for j := len(s); j > 0; {
+		r, size := utf8.DecodeLastRuneInString(s[:j])
+		j -= size
+		i--
+		if i < 0 || w[i] != r {
+			return -1
+		}
+	}
+	return i
+}
+
+//
+// endsWith reports whether w ends with s.
+//
+func endsWith(w []rune, s string) bool {
+	return suffixStart(w, s) >= 0
+}
+
+//
+// replaceSuffix replaces the end of w from start with s.
+//
+func replaceSuffix(w []rune, start int, s string) []rune {
+	return append(w[:start], []rune(s)...)
+}
+
+//
+// inGroup reports whether the rune of w at i exists and is one of the runes
+// of group.
+//
+func inGroup(w []rune, i int, group string) bool {
+	return i >= 0 && i < len(w) && strings.ContainsRune(group, w[i])
+}
+
+//
+// regionAfter returns the start of the region after the first non-vowel
+// following a vowel at or after start: R1 from 0, R2 from R1.
+//
+func regionAfter(w []rune, start int, vowels string) int {
+	for i := start + 1; i < len(w); i++ {
+		if !inGroup(w, i, vowels) && inGroup(w, i-1, vowels) {
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+//
+// romanceRV returns the start of RV for Spanish, Portuguese and Italian: if
+// the second letter is a consonant, RV is after the next vowel; if the first
+// two letters are vowels, RV is after the next consonant; otherwise RV is
+// after the third letter.
+//
+func romanceRV(w []rune, vowels string) int {
+	if len(w) < 2 {
+		return len(w)
+	}
+	next := func(i int, vowel bool) int {
+		for ; i < len(w); i++ {
+			if inGroup(w, i, vowels) == vowel {
+				return i + 1
+			}
+		}
+		return len(w)
+	}
+	switch {
+	case !inGroup(w, 1, vowels):
+		return next(2, true)
+	case inGroup(w, 0, vowels):
+		return next(2, false)
+	case len(w) > 2:
+		return 3
+	}
+	return len(w)
+}
@@ -0,0 +1,99 @@
+package stemmer
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestLanguages(t *testing.T) {
+	fixtures := []struct {
+		code string
+		word string
+	}{
+		{"da", "udviklingen"},
+		{"de", "aufeinanderfolgenden"},
+		{"es", "chicas"},
+		{"fi", "kirjoissa"},
+		{"fr", "continuellement"},
+		{"it", "abbandonata"},
+		{"nl", "lichamelijkheden"},
+		{"no", "hemmelighetene"},
+		{"pt", "nações"},
+		{"ru", "красивейшего"},
+		{"sv", "jaktkarlarne"},
+	}
+
+	stemmed := []string{
+		"udvikling",
+		"aufeinanderfolg",
+		"chic",
+		"kirj",
+		"continuel",
+		"abbandon",
+		"licham",
+		"hemm",
+		"naçõ",
+		"красив",
+		"jaktkarl",
+	}
+
+	for k, value := range fixtures {
+		s, ok := Lookup(value.code)
+		if !ok {
+			t.Fatalf("Lookup() did not find stemmer '%s'", value.code)
+		}
+		if result := s.StemString(value.word); result != stemmed[k] {
+			t.Errorf("%s StemString() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value.code, value.word, result, stemmed[k])
+		}
+		if result := s.Stem([]byte(" " + value.word + " ")); !bytes.Equal(result, []byte(stemmed[k])) {
+			t.Errorf("%s Stem() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value.code, value.word, result, stemmed[k])
+		}
+	}
+}
+
+//
+// The vocabularies of de, es, fr, nl, no, ru and sv, and their stems, are the
+// ones published with Snowball. Those of da, fi, it and pt are the words of
+// the Danish, Finnish, Italian and Portuguese translations of Syncthing and
+// Gitea, stemmed by the C code of Snowball 2.0.0.
+//
+func TestLanguagesVocal(t *testing.T) {
+	for _, l := range languages {
+		v, err := os.Open("testdata/" + l.code + "/voc.txt")
+		if err != nil {
+			panic(err)
+		}
+		vocScanner := bufio.NewScanner(v)
+
+		o, err := os.Open("testdata/" + l.code + "/output.txt")
+		if err != nil {
+			panic(err)
+		}
+		outScanner := bufio.NewScanner(o)
+
+		for vocScanner.Scan() {
+			outScanner.Scan()
+			word := vocScanner.Bytes()
+			stem := outScanner.Bytes()
+
+			if result := l.Stem(word); !bytes.Equal(result, stem) {
+				t.Errorf("%s Stem() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", l.code, word, result, stem)
+			}
+		}
+		v.Close()
+		o.Close()
+	}
+}
+
+func BenchmarkLanguages(b *testing.B) {
+	word := []byte("troubles")
+	for _, l := range languages {
+		b.Run(l.code, func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				l.Stem(word)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package stemmer
+
+import (
+	"bytes"
+	"strings"
+)
+
+//
+// Lovins is the algorithm of J. B. Lovins, "Development of a stemming
+// algorithm", Mechanical Translation and Computational Linguistics 11, 1968,
+// as published on the Snowball site:
+//
+//    https://snowballstem.org/algorithms/lovins/stemmer.html
+//
+// The longest of the 294 endings whose condition holds is removed, in a
+// single pass, then the stem is recoded by the 35 transformation rules: a
+// double letter is undoubled and one respelling applies.
+//
+
+//
+// The endings, with the letter of their condition. The endings not listed
+// here are in lovinsEndingsA.
+//
+var lovinsEndings = map[string]string{
+	"alistically": "B", "arizability": "A", "izationally": "B",
+
+	"allically": "C", "ationally": "B", "eableness": "E",
+
+	"alistic": "B", "ariness": "E", "ational": "B", "elihood": "E",
+	"ication": "G", "ization": "F",
+
+	"acious": "B", "action": "G", "ancing": "B", "ations": "B",
+	"eature": "Z", "enting": "C", "ionate": "D", "izable": "E",
+
+	"aging": "B", "alism": "B", "allic": "BB", "anced": "B", "ances": "B",
+	"antic": "C", "arity": "B", "ating": "I", "ation": "B", "ature": "E",
+	"early": "Y", "eness": "E", "ening": "E", "ented": "C", "idine": "I",
+	"ingly": "B", "inism": "J", "inity": "CC", "izers": "F", "izing": "F",
+
+	"ages": "B", "ally": "B", "ance": "B", "ancy": "B", "ants": "B",
+	"arly": "K", "ated": "I", "atic": "B", "ealy": "Y", "edly": "E",
+	"ened": "E", "enly": "E", "ides": "L", "ines": "M", "ings": "N",
+	"ions": "B", "isms": "B", "itic": "H", "ized": "F", "izer": "F",
+	"ying": "B",
+
+	"age": "B", "als": "BB", "ant": "B", "ars": "O", "ary": "F", "eal": "Y",
+	"ear": "Y", "ely": "E", "ene": "E", "ent": "C", "ery": "E", "ide": "L",
+	"ies": "P", "ine": "M", "ing": "N", "ion": "Q", "ish": "C", "ism": "B",
+	"ite": "AA", "ize": "F", "one": "R",
+
+	"al": "BB", "ar": "X", "as": "B", "ed": "E", "en": "F", "es": "E",
+	"ly": "B", "on": "S", "or": "T", "um": "U", "us": "V", "yl": "R",
+
+	"s": "W", "y": "B",
+}
+
+//
+// The endings without any condition but the minimum stem length of 2.
+//
+var lovinsEndingsA = []string{
+	"antialness", "arisations", "arizations", "entialness",
+
+	"antaneous", "antiality", "arisation", "arization", "ativeness",
+	"entations", "entiality", "entialize", "entiation", "ionalness",
+	"istically", "itousness", "izability", "izational",
+
+	"ableness", "arizable", "entation", "entially", "eousness", "ibleness",
+	"icalness", "ionalism", "ionality", "ionalize", "iousness", "izations",
+	"lessness",
+
+	"ability", "aically", "alities", "aristic", "arizing", "ateness",
+	"atingly", "atively", "ativism", "encible", "entally", "entials",
+	"entiate", "entness", "fulness", "ibility", "icalism", "icalist",
+	"icality", "icalize", "icianry", "ination", "ingness", "ionally",
+	"isation", "ishness", "istical", "iteness", "iveness", "ivistic",
+	"ivities", "izement", "oidally", "ousness",
+
+	"aceous", "alness", "ancial", "ancies", "ariser", "arized", "arizer",
+	"atable", "atives", "efully", "encies", "encing", "ential", "entist",
+	"eously", "ialist", "iality", "ialize", "ically", "icance", "icians",
+	"icists", "ifully", "ionals", "ioning", "ionist", "iously", "istics",
+	"lessly", "nesses", "oidism",
+
+	"acies", "acity", "aical", "alist", "ality", "alize", "arial", "aries",
+	"arily", "arize", "aroid", "ately", "ative", "ators", "atory", "ehood",
+	"eless", "elity", "ement", "enced", "ences", "ental", "ently", "fully",
+	"ially", "icant", "ician", "icide", "icism", "icist", "icity", "iedly",
+	"ihood", "inate", "iness", "ional", "ioned", "ished", "istic", "ities",
+	"itous", "ively", "ivity", "oidal", "oides", "otide", "ously",
+
+	"able", "ably", "aric", "ates", "ator", "eful", "eity", "ence",
+	"ency", "eous", "hood", "ials", "ians", "ible", "ibly", "ical", "iers",
+	"iful", "ious", "ists", "less", "lily", "ness", "ogen", "ward", "wise",
+	"yish",
+
+	"acy", "aic", "ata", "ate", "ese", "ful", "ial", "ian", "ics", "ied",
+	"ier", "ily", "ist", "ity", "ium", "ive", "oid", "ous",
+
+	"ae", "ia", "ic", "is", "'s", "s'",
+
+	"a", "e", "i", "o",
+}
+
+func init() {
+	for _, ending := range lovinsEndingsA {
+		lovinsEndings[ending] = "A"
+	}
+}
+
+//
+// lovinsCondition reports whether stem satisfies the condition named by
+// code. Every condition requires a stem of at least 2 letters.
+//
+func lovinsCondition(code string, stem []byte) bool {
+	n := len(stem)
+	if n < 2 {
+		return false
+	}
+	ends := func(s string) bool { return bytes.HasSuffix(stem, []byte(s)) }
+	switch code {
+	case "A":
+		return true
+	case "B":
+		return n >= 3
+	case "C":
+		return n >= 4
+	case "D":
+		return n >= 5
+	case "E":
+		return !ends("e")
+	case "F":
+		return n >= 3 && !ends("e")
+	case "G":
+		return n >= 3 && ends("f")
+	case "H":
+		return ends("t") || ends("ll")
+	case "I":
+		return !ends("o") && !ends("e")
+	case "J":
+		return !ends("a") && !ends("e")
+	case "K":
+		return n >= 3 && (ends("l") || ends("i") || ends("e") && stem[n-3] == 'u')
+	case "L":
+		return !ends("u") && !ends("x") && (!ends("s") || ends("os"))
+	case "M":
+		return !ends("a") && !ends("c") && !ends("e") && !ends("m")
+	case "N":
+		return n >= 3 && (stem[n-3] != 's' || n >= 4)
+	case "O":
+		return ends("l") || ends("i")
+	case "P":
+		return !ends("c")
+	case "Q":
+		return n >= 3 && !ends("l") && !ends("n")
+	case "R":
+		return ends("n") || ends("r")
+	case "S":
+		return ends("dr") || ends("t") && !ends("tt")
+	case "T":
+		return ends("s") || ends("t") && !ends("ot")
+	case "U":
+		return ends("l") || ends("m") || ends("n") || ends("r")
+	case "V":
+		return ends("c")
+	case "W":
+		return !ends("s") && !ends("u")
+	case "X":
+		return ends("l") || ends("i") || n >= 3 && ends("e") && stem[n-3] == 'u'
+	case "Y":
+		return ends("in")
+	case "Z":
+		return !ends("f")
+	case "AA":
+		for _, s := range []string{"d", "f", "ph", "th", "l", "er", "or", "es", "t"} {
+			if ends(s) {
+				return true
+			}
+		}
+		return false
+	case "BB":
+		return n >= 3 && !ends("met") && !ends("ryst")
+	case "CC":
+		return ends("l")
+	}
+	return false
+}
+
+//
+// The respellings of the recoding, longest match first. except lists the
+// letters that must not come before the match for the rule to apply.
+//
+var lovinsRespellings = []struct {
+	suffix, replacement, except string
+}{
+	{"umpt", "um", ""},
+	{"istr", "ister", ""},
+	{"metr", "meter", ""},
+	{"erid", "eris", ""},
+	{"pand", "pans", ""},
+	{"iev", "ief", ""},
+	{"uct", "uc", ""},
+	{"rpt", "rb", ""},
+	{"urs", "ur", ""},
+	{"olv", "olut", ""},
+	{"bex", "bic", ""},
+	{"dex", "dic", ""},
+	{"pex", "pic", ""},
+	{"tex", "tic", ""},
+	{"lux", "luc", ""},
+	{"uad", "uas", ""},
+	{"vad", "vas", ""},
+	{"cid", "cis", ""},
+	{"lid", "lis", ""},
+	{"end", "ens", "s"},
+	{"ond", "ons", ""},
+	{"lud", "lus", ""},
+	{"rud", "rus", ""},
+	{"her", "hes", "pt"},
+	{"mit", "mis", ""},
+	{"ent", "ens", "m"},
+	{"ert", "ers", ""},
+	{"ul", "l", "aio"},
+	{"ax", "ac", ""},
+	{"ex", "ec", ""},
+	{"ix", "ic", ""},
+	{"et", "es", "n"},
+	{"yt", "ys", ""},
+	{"yz", "ys", ""},
+}
+
+//
+// lovinsRecode undoubles the final letter of stem, then respells its end.
+//
+func lovinsRecode(stem []byte) []byte {
+	if n := len(stem); n >= 2 && stem[n-1] == stem[n-2] {
+		switch stem[n-1] {
+		case 'b', 'd', 'g', 'l', 'm', 'n', 'p', 'r', 's', 't':
+			stem = stem[:n-1]
+		}
+	}
+	for _, r := range lovinsRespellings {
+		if !bytes.HasSuffix(stem, []byte(r.suffix)) {
+			continue
+		}
+		start := len(stem) - len(r.suffix)
+		if start > 0 && r.except != "" && strings.IndexByte(r.except, stem[start-1]) >= 0 {
+			break
+		}
+		return append(stem[:start], r.replacement...)
+	}
+	return stem
+}
+
+//
+// StemLovins returns the stem of word according to the Lovins algorithm.
+//
+func StemLovins(word []byte) []byte {
+	word = bytes.TrimSpace(appendLower(make([]byte, 0, len(word)), word))
+	longest := len(word) - 2
+	if longest > 11 {
+		longest = 11
+	}
+	for l := longest; l > 0; l-- {
+		code, ok := lovinsEndings[string(word[len(word)-l:])]
+		if ok && lovinsCondition(code, word[:len(word)-l]) {
+			word = word[:len(word)-l]
+			break
+		}
+	}
+	return lovinsRecode(word)
+}
@@ -0,0 +1,145 @@
+package stemmer
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestLovinsCondition(t *testing.T) {
+	fixtures := []struct {
+		code string
+		stem word
+	}{
+		{"A", []byte("ab")},
+		{"A", []byte("a")},
+		{"B", []byte("ab")},
+		{"E", []byte("abe")},
+		{"G", []byte("abf")},
+		{"K", []byte("abute")},
+		{"L", []byte("abos")},
+		{"L", []byte("abs")},
+		{"N", []byte("abs")},
+		{"N", []byte("sab")},
+		{"S", []byte("abtt")},
+		{"T", []byte("abot")},
+		{"AA", []byte("graph")},
+		{"BB", []byte("crys")},
+		{"BB", []byte("cryst")},
+	}
+
+	holds := []bool{true, false, false, false, true, true, true, false, true, false, false, false, true, true, false}
+
+	for k, value := range fixtures {
+		if result := lovinsCondition(value.code, value.stem); result != holds[k] {
+			t.Errorf("lovinsCondition() return value not what was expected, pass: '%s %s' return: '%v' expected: '%v'", value.code, value.stem, result, holds[k])
+		}
+	}
+}
+
+func TestLovinsRecode(t *testing.T) {
+	fixtures := []word{
+		[]byte("sitt"),
+		[]byte("believ"),
+		[]byte("absorpt"),
+		[]byte("matrix"),
+		[]byte("specul"),
+		[]byte("soul"),
+		[]byte("defend"),
+		[]byte("send"),
+		[]byte("adher"),
+		[]byte("comment"),
+		[]byte("parent"),
+	}
+
+	recoded := []word{
+		[]byte("sit"),
+		[]byte("belief"),
+		[]byte("absorb"),
+		[]byte("matric"),
+		[]byte("specl"),
+		[]byte("soul"),
+		[]byte("defens"),
+		[]byte("send"),
+		[]byte("adhes"),
+		[]byte("comment"),
+		[]byte("parens"),
+	}
+
+	for k, value := range fixtures {
+		if result := lovinsRecode(append([]byte(nil), value...)); !bytes.Equal(result, recoded[k]) {
+			t.Errorf("lovinsRecode() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, recoded[k])
+		}
+	}
+}
+
+func TestStemLovins(t *testing.T) {
+	fixtures := []word{
+		[]byte("nationally"),
+		[]byte("Sitting"),
+		[]byte("magnesia"),
+		[]byte("matrix"),
+		[]byte("absorption"),
+		[]byte("dissolution"),
+		[]byte("dissolve"),
+		[]byte("ox"),
+	}
+
+	stemmed := []word{
+		[]byte("nat"),
+		[]byte("sit"),
+		[]byte("magnes"),
+		[]byte("matric"),
+		[]byte("absorb"),
+		[]byte("dissolut"),
+		[]byte("dissolut"),
+		[]byte("ox"),
+	}
+
+	for k, value := range fixtures {
+		if result := StemLovins(value); !bytes.Equal(result, stemmed[k]) {
+			t.Errorf("StemLovins() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, stemmed[k])
+		}
+	}
+}
+
+//
+// testdata/lovins/output.txt holds the stems StemLovins gives for voc.txt, a
+// snapshot guarding against regressions. Package snowball gives the same stems
+// running the Snowball definition, snowball/testdata/lovins.sbl, but that is
+// this repository checking itself: the stems have not been compared with
+// those of another implementation.
+//
+func TestLovinsVocal(t *testing.T) {
+	v, err := os.Open("voc.txt")
+	if err != nil {
+		panic(err)
+	}
+	defer v.Close()
+	vocScanner := bufio.NewScanner(v)
+
+	o, err := os.Open("testdata/lovins/output.txt")
+	if err != nil {
+		panic(err)
+	}
+	defer o.Close()
+	outScanner := bufio.NewScanner(o)
+
+	for vocScanner.Scan() {
+		outScanner.Scan()
+		word := vocScanner.Bytes()
+		stem := outScanner.Bytes()
+
+		if result := StemLovins(word); !bytes.Equal(result, stem) {
+			t.Errorf("StemLovins() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", word, result, stem)
+		}
+	}
+}
+
+func BenchmarkStemLovins(b *testing.B) {
+	word := []byte("troubles")
+	for n := 0; n < b.N; n++ {
+		StemLovins(word)
+	}
+}
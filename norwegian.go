@@ -0,0 +1,52 @@
+package stemmer
+
+//
+// The Norwegian (Bokmål) stemmer:
+//
+//    https://snowballstem.org/algorithms/norwegian/stemmer.html
+//
+
+const norwegianVowels = "aeiouyæåø"
+
+var norwegianMainSuffixes = newSuffixList(
+	"a", "e", "ede", "ande", "ende", "ane", "ene", "hetene", "en", "heten",
+	"ar", "er", "heter", "as", "es", "edes", "endes", "enes", "hetenes",
+	"ens", "hetens", "ers", "ets", "et", "het", "ast", "s", "erte", "ert",
+)
+
+var norwegianOtherSuffixes = newSuffixList(
+	"leg", "eleg", "ig", "eig", "lig", "elig", "els", "lov", "elov", "slov",
+	"hetslov",
+)
+
+func stemNorwegian(w []rune) []rune {
+	r1 := scandinavianR1(w, norwegianVowels)
+
+	// Step 1: the main suffixes in R1; s only after a valid s-ending, erte
+	// and ert become er.
+	switch s, start := norwegianMainSuffixes.find(w, r1); s {
+	case "":
+	case "s":
+		if inGroup(w, start-1, "bcdfghjlmnoprtvyz") || inGroup(w, start-1, "k") && !inGroup(w, start-2, norwegianVowels) {
+			w = w[:start]
+		}
+	case "erte", "ert":
+		w = replaceSuffix(w, start, "er")
+	default:
+		w = w[:start]
+	}
+
+	// Step 2: undouble dt and vt in R1.
+	for _, s := range []string{"dt", "vt"} {
+		if suffixStart(w, s) >= r1 {
+			w = w[:len(w)-1]
+			break
+		}
+	}
+
+	// Step 3: the other suffixes in R1.
+	if s, start := norwegianOtherSuffixes.find(w, r1); s != "" {
+		w = w[:start]
+	}
+	return w
+}
@@ -0,0 +1,79 @@
+package stemmer
+
+//
+// Light stemmers that only remove plurals, for searches where Porter
+// conflates too much ("university" and "universe" both stem to "univers").
+// Like Step 1a of Stem, which they replace, they are rule tables run by a
+// RuleStemmer, so words shorter than three letters are left alone.
+//
+
+//
+// The S-stemmer of D. Harman, "How effective is suffixing?", JASIS 42(1),
+// 1991. The first rule that matches applies:
+//
+//    IES -> Y   but not after E or A    ponies    ->  pony
+//    ES  -> E   but not after A, E or O horses    ->  horse
+//    S   ->     but not after U or S    cats      ->  cat
+//
+// so that "aies" falls through to the ES rule and "aes" is left alone.
+//
+var SStemmerStep = pluralStep("s",
+	Rule{Suffix: "ies", Replacement: "y"},
+	Rule{Suffix: "aies", Replacement: "aie"},
+	Rule{Suffix: "eies", Replacement: "eie"},
+)
+
+//
+// The minimal plural stemmer of Lucene's EnglishMinimalStemFilter:
+//
+//    IES -> Y   after a letter other    ponies    ->  pony
+//               than E or A
+//    S   ->     but not after U, S, or  cats      ->  cat
+//               after AE, EE, OE or IE  horses    ->  horse
+//                                       toes      ->  toes
+//
+// Unlike the S-stemmer it leaves "aies" alone, and "ies" on its own, which
+// Lucene only shortens when it is longer than three letters.
+//
+var MinimalPluralStep = pluralStep("plural",
+	Rule{Suffix: "ies", Replacement: "y", Condition: "*[bcdfghijklmnopqrstuvwxyz]"},
+)
+
+//
+// pluralStep returns the step name with the rules both plural stemmers
+// share, those of Step 1a for S and SS and the ES endings left alone, and
+// the IES rules of the stemmer.
+//
+func pluralStep(name string, ies ...Rule) Step {
+	return Step{
+		Name: name,
+		Rules: append([]Rule{
+			{Suffix: "aes", Replacement: "aes"},
+			{Suffix: "ees", Replacement: "ees"},
+			{Suffix: "oes", Replacement: "oes"},
+			{Suffix: "s", Replacement: ""},
+			{Suffix: "us", Replacement: "us"},
+			{Suffix: "ss", Replacement: "ss"},
+		}, ies...),
+	}
+}
+
+var (
+	sStemmerRules      = MustNewRuleStemmer("s", []Step{SStemmerStep})
+	minimalPluralRules = MustNewRuleStemmer("plural", []Step{MinimalPluralStep})
+)
+
+//
+// StemS returns the stem of word according to the Harman S-stemmer.
+//
+func StemS(word []byte) []byte {
+	return sStemmerRules.Stem(word)
+}
+
+//
+// StemPlural returns word without its plural, as Lucene's minimal English
+// stemmer does.
+//
+func StemPlural(word []byte) []byte {
+	return minimalPluralRules.Stem(word)
+}
@@ -0,0 +1,144 @@
+package stemmer
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestStemS(t *testing.T) {
+	fixtures := []word{
+		[]byte("ponies"),
+		[]byte("Horses"),
+		[]byte("cats"),
+		[]byte("universities"),
+		[]byte("universes"),
+		[]byte("status"),
+		[]byte("caress"),
+		[]byte("toes"),
+		[]byte("trees"),
+		[]byte("algae"),
+		[]byte("faies"),
+		[]byte("as"),
+		[]byte("ies"),
+	}
+
+	stemmed := []word{
+		[]byte("pony"),
+		[]byte("horse"),
+		[]byte("cat"),
+		[]byte("university"),
+		[]byte("universe"),
+		[]byte("status"),
+		[]byte("caress"),
+		[]byte("toes"),
+		[]byte("trees"),
+		[]byte("algae"),
+		[]byte("faie"),
+		[]byte("as"),
+		[]byte("y"),
+	}
+
+	for k, value := range fixtures {
+		if result := StemS(value); !bytes.Equal(result, stemmed[k]) {
+			t.Errorf("StemS() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, stemmed[k])
+		}
+	}
+}
+
+func TestStemPlural(t *testing.T) {
+	fixtures := []word{
+		[]byte("ponies"),
+		[]byte("Horses"),
+		[]byte("cats"),
+		[]byte("universities"),
+		[]byte("status"),
+		[]byte("toes"),
+		[]byte("faies"),
+		[]byte("boxes"),
+		[]byte("ies"),
+		[]byte("pies"),
+		// The examples of Lucene's TestEnglishMinimalStemFilter.
+		[]byte("queries"),
+		[]byte("phrases"),
+		[]byte("corpus"),
+		[]byte("stress"),
+		[]byte("kings"),
+		[]byte("panels"),
+		[]byte("aerodynamics"),
+		[]byte("congress"),
+		[]byte("serious"),
+	}
+
+	stemmed := []word{
+		[]byte("pony"),
+		[]byte("horse"),
+		[]byte("cat"),
+		[]byte("university"),
+		[]byte("status"),
+		[]byte("toes"),
+		[]byte("faies"),
+		[]byte("boxe"),
+		[]byte("ies"),
+		[]byte("py"),
+		[]byte("query"),
+		[]byte("phrase"),
+		[]byte("corpus"),
+		[]byte("stress"),
+		[]byte("king"),
+		[]byte("panel"),
+		[]byte("aerodynamic"),
+		[]byte("congress"),
+		[]byte("serious"),
+	}
+
+	for k, value := range fixtures {
+		if result := StemPlural(value); !bytes.Equal(result, stemmed[k]) {
+			t.Errorf("StemPlural() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, stemmed[k])
+		}
+	}
+}
+
+//
+// testdata/s/output.txt and testdata/plural/output.txt hold the stems StemS
+// and StemPlural give for voc.txt, snapshots guarding against regressions;
+// only the Lucene examples above come from another implementation.
+//
+func TestPluralVocal(t *testing.T) {
+	fixtures := []func([]byte) []byte{StemS, StemPlural}
+	outputs := []string{"testdata/s/output.txt", "testdata/plural/output.txt"}
+
+	for k, stem := range fixtures {
+		v, err := os.Open("voc.txt")
+		if err != nil {
+			panic(err)
+		}
+		defer v.Close()
+		vocScanner := bufio.NewScanner(v)
+
+		o, err := os.Open(outputs[k])
+		if err != nil {
+			panic(err)
+		}
+		defer o.Close()
+		outScanner := bufio.NewScanner(o)
+
+		for vocScanner.Scan() {
+			outScanner.Scan()
+			word := vocScanner.Bytes()
+			expected := outScanner.Bytes()
+
+			if result := stem(word); !bytes.Equal(result, expected) {
+				t.Errorf("%s return value not what was expected, pass: '%s' return: '%s' expected: '%s'", outputs[k], word, result, expected)
+			}
+		}
+	}
+}
+
+func BenchmarkStemS(b *testing.B) {
+	word := []byte("troubles")
+	for n := 0; n < b.N; n++ {
+		StemS(word)
+	}
+}
@@ -0,0 +1,490 @@
+package stemmer
+
+import "bytes"
+
+//
+// Porter2 is the revised Porter algorithm published as the Snowball English
+// stemmer:
+//
+//    https://snowballstem.org/algorithms/english/stemmer.html
+//
+// Inside the step functions a 'y' that acts as a consonant is kept as an
+// upper case 'Y', exactly as the Snowball definition does, and is turned
+// back into 'y' once all the steps are done.
+//
+
+type porter2Rule struct {
+	suffix, replacement string
+}
+
+//
+// Words that are stemmed as a whole, before any of the steps run.
+//
+var porter2Exceptions = map[string]string{
+	"skis":   "ski",
+	"skies":  "sky",
+	"dying":  "die",
+	"lying":  "lie",
+	"tying":  "tie",
+	"idly":   "idl",
+	"gently": "gentl",
+	"ugly":   "ugli",
+	"early":  "earli",
+	"only":   "onli",
+	"singly": "singl",
+	"sky":    "sky",
+	"news":   "news",
+	"howe":   "howe",
+	"atlas":  "atlas",
+	"cosmos": "cosmos",
+	"bias":   "bias",
+	"andes":  "andes",
+}
+
+//
+// Words that are left alone once Step 1a has been applied.
+//
+var porter2Invariants = map[string]bool{
+	"inning":  true,
+	"outing":  true,
+	"canning": true,
+	"herring": true,
+	"earring": true,
+	"proceed": true,
+	"exceed":  true,
+	"succeed": true,
+}
+
+//
+// Prefixes that fix the start of R1 regardless of the usual rule.
+//
+var porter2Prefixes = [][]byte{
+	[]byte("gener"),
+	[]byte("commun"),
+	[]byte("arsen"),
+}
+
+func porter2Vowel(b byte) bool {
+	switch b {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+func porter2ContainVowel(word []byte) bool {
+	for _, b := range word {
+		if porter2Vowel(b) {
+			return true
+		}
+	}
+	return false
+}
+
+//
+// A short syllable is either a vowel followed by a non-vowel other than W, X
+// or Y and preceded by a non-vowel, or a vowel at the beginning of the word
+// followed by a non-vowel.
+//
+func porter2ShortSyllable(word []byte) bool {
+	l := len(word)
+	if l >= 3 && !porter2Vowel(word[l-3]) && porter2Vowel(word[l-2]) && !porter2Vowel(word[l-1]) {
+		return word[l-1] != 'w' && word[l-1] != 'x' && word[l-1] != 'Y'
+	}
+	return l == 2 && porter2Vowel(word[0]) && !porter2Vowel(word[1])
+}
+
+//
+// porter2After returns the position just after the first non-vowel that
+// follows a vowel, starting the search at i, or len(word) if there is none.
+//
+func porter2After(word []byte, i int) int {
+	for ; i < len(word) && !porter2Vowel(word[i]); i++ {
+	}
+	for ; i < len(word) && porter2Vowel(word[i]); i++ {
+	}
+	if i < len(word) {
+		return i + 1
+	}
+	return len(word)
+}
+
+//
+// R1 is the region after the first non-vowel following a vowel, or the end of
+// the word if there is no such non-vowel. R2 is the region after the first
+// non-vowel following a vowel in R1.
+//
+func porter2Regions(word []byte) (r1, r2 int) {
+	r1 = -1
+	for _, prefix := range porter2Prefixes {
+		if bytes.HasPrefix(word, prefix) {
+			r1 = len(prefix)
+			break
+		}
+	}
+	if r1 < 0 {
+		r1 = porter2After(word, 0)
+	}
+	return r1, porter2After(word, r1)
+}
+
+//
+// Set initial y, or y after a vowel, to Y.
+//
+func porter2MarkY(word []byte) {
+	if len(word) > 0 && word[0] == 'y' {
+		word[0] = 'Y'
+	}
+	for i := 1; i < len(word); i++ {
+		if word[i] == 'y' && porter2Vowel(word[i-1]) {
+			word[i] = 'Y'
+		}
+	}
+}
+
+//
+// Step 0
+//
+//    's'  ->
+//    's   ->
+//    '    ->
+//
+func porter2Step0(word []byte) []byte {
+	if bytes.HasSuffix(word, []byte("'s'")) {
+		return word[:len(word)-3]
+	} else if bytes.HasSuffix(word, []byte("'s")) {
+		return word[:len(word)-2]
+	} else if bytes.HasSuffix(word, []byte("'")) {
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+//
+// Step 1a
+//
+//    SSES    -> SS                      caresses  ->  caress
+//    IED IES -> I if preceded by more   cries     ->  cri
+//               than one letter,
+//               otherwise IE            ties      ->  tie
+//    US SS   ->                         gas       ->  gas
+//    S       -> delete if the preceding gaps      ->  gap
+//               part contains a vowel
+//               not immediately before
+//               the S
+//
+func porter2Step1a(word []byte) []byte {
+	l := len(word)
+	if bytes.HasSuffix(word, []byte("sses")) {
+		return word[:l-2]
+	} else if bytes.HasSuffix(word, []byte("ied")) || bytes.HasSuffix(word, []byte("ies")) {
+		if l > 4 {
+			return word[:l-2]
+		}
+		return word[:l-1]
+	} else if bytes.HasSuffix(word, []byte("us")) || bytes.HasSuffix(word, []byte("ss")) {
+		return word
+	} else if bytes.HasSuffix(word, []byte("s")) {
+		if l > 2 && porter2ContainVowel(word[:l-2]) {
+			return word[:l-1]
+		}
+	}
+	return word
+}
+
+//
+// Step 1b
+//
+//    EED EEDLY         -> EE if in R1   proceedly  ->  proceed
+//    ED EDLY ING INGLY -> delete if the preceding part contains a vowel,
+//                         and then
+//        AT BL IZ      -> add E         luxuriating -> luxuriate
+//        double        -> single letter hopping    ->  hop
+//        short word    -> add E         hoping     ->  hope
+//
+func porter2Step1b(word []byte, r1 int) []byte {
+	l := len(word)
+	if bytes.HasSuffix(word, []byte("eedly")) {
+		if l-5 >= r1 {
+			return word[:l-3]
+		}
+		return word
+	} else if bytes.HasSuffix(word, []byte("eed")) {
+		if l-3 >= r1 {
+			return word[:l-1]
+		}
+		return word
+	}
+
+	var stem []byte
+	if bytes.HasSuffix(word, []byte("ingly")) {
+		stem = word[:l-5]
+	} else if bytes.HasSuffix(word, []byte("edly")) {
+		stem = word[:l-4]
+	} else if bytes.HasSuffix(word, []byte("ing")) {
+		stem = word[:l-3]
+	} else if bytes.HasSuffix(word, []byte("ed")) {
+		stem = word[:l-2]
+	} else {
+		return word
+	}
+	if !porter2ContainVowel(stem) {
+		return word
+	}
+
+	l = len(stem)
+	if bytes.HasSuffix(stem, []byte("at")) || bytes.HasSuffix(stem, []byte("bl")) || bytes.HasSuffix(stem, []byte("iz")) {
+		return append(stem, 'e')
+	} else if porter2Double(stem) {
+		return stem[:l-1]
+	} else if l == r1 && porter2ShortSyllable(stem) {
+		return append(stem, 'e')
+	}
+	return stem
+}
+
+//
+// porter2Double reports whether word ends with one of the doubles BB, DD, FF,
+// GG, MM, NN, PP, RR or TT.
+//
+func porter2Double(word []byte) bool {
+	l := len(word)
+	if l < 2 || word[l-1] != word[l-2] {
+		return false
+	}
+	switch word[l-1] {
+	case 'b', 'd', 'f', 'g', 'm', 'n', 'p', 'r', 't':
+		return true
+	}
+	return false
+}
+
+//
+// Step 1c
+//
+//    Y -> I if preceded by a non-vowel  cry       ->  cri
+//           which is not the first      by        ->  by
+//           letter of the word          say       ->  say
+//
+func porter2Step1c(word []byte) []byte {
+	l := len(word)
+	if l > 2 && (word[l-1] == 'y' || word[l-1] == 'Y') && !porter2Vowel(word[l-2]) {
+		word[l-1] = 'i'
+	}
+	return word
+}
+
+//
+// Step 2, in R1
+//
+//    TIONAL  ->  TION      ENCI    ->  ENCE      ANCI    ->  ANCE
+//    ABLI    ->  ABLE      ENTLI   ->  ENT       IZER    ->  IZE
+//    IZATION ->  IZE       ATIONAL ->  ATE       ATION   ->  ATE
+//    ATOR    ->  ATE       ALISM   ->  AL        ALITI   ->  AL
+//    ALLI    ->  AL        FULNESS ->  FUL       OUSLI   ->  OUS
+//    OUSNESS ->  OUS       IVENESS ->  IVE       IVITI   ->  IVE
+//    BILITI  ->  BLE       BLI     ->  BLE       FULLI   ->  FUL
+//    LESSLI  ->  LESS      OGI     ->  OG if preceded by L
+//    LI      ->  delete if preceded by a valid li-ending
+//
+var porter2Step2Rules = []porter2Rule{
+	{"ational", "ate"},
+	{"fulness", "ful"},
+	{"iveness", "ive"},
+	{"ization", "ize"},
+	{"ousness", "ous"},
+	{"biliti", "ble"},
+	{"lessli", "less"},
+	{"tional", "tion"},
+	{"alism", "al"},
+	{"aliti", "al"},
+	{"ation", "ate"},
+	{"entli", "ent"},
+	{"fulli", "ful"},
+	{"iviti", "ive"},
+	{"ousli", "ous"},
+	{"abli", "able"},
+	{"alli", "al"},
+	{"anci", "ance"},
+	{"ator", "ate"},
+	{"enci", "ence"},
+	{"izer", "ize"},
+	{"bli", "ble"},
+	{"ogi", "og"},
+	{"li", ""},
+}
+
+func porter2Step2(word []byte, r1 int) []byte {
+	rule, ok := porter2Match(word, porter2Step2Rules)
+	if !ok {
+		return word
+	}
+	stem := word[:len(word)-len(rule.suffix)]
+	if len(stem) < r1 {
+		return word
+	}
+	switch rule.suffix {
+	case "ogi":
+		if !bytes.HasSuffix(stem, []byte("l")) {
+			return word
+		}
+	case "li":
+		if len(stem) == 0 || !porter2ValidLi(stem[len(stem)-1]) {
+			return word
+		}
+	}
+	return append(stem, rule.replacement...)
+}
+
+func porter2ValidLi(b byte) bool {
+	switch b {
+	case 'c', 'd', 'e', 'g', 'h', 'k', 'm', 'n', 'r', 't':
+		return true
+	}
+	return false
+}
+
+//
+// Step 3, in R1
+//
+//    TIONAL  ->  TION      ATIONAL ->  ATE       ALIZE   ->  AL
+//    ICATE   ->  IC        ICITI   ->  IC        ICAL    ->  IC
+//    FUL     ->            NESS    ->
+//    ATIVE   ->  delete if in R2
+//
+var porter2Step3Rules = []porter2Rule{
+	{"ational", "ate"},
+	{"tional", "tion"},
+	{"alize", "al"},
+	{"ative", ""},
+	{"icate", "ic"},
+	{"iciti", "ic"},
+	{"ical", "ic"},
+	{"ness", ""},
+	{"ful", ""},
+}
+
+func porter2Step3(word []byte, r1, r2 int) []byte {
+	rule, ok := porter2Match(word, porter2Step3Rules)
+	if !ok {
+		return word
+	}
+	stem := word[:len(word)-len(rule.suffix)]
+	if len(stem) < r1 || (rule.suffix == "ative" && len(stem) < r2) {
+		return word
+	}
+	return append(stem, rule.replacement...)
+}
+
+//
+// Step 4, in R2
+//
+//    AL ANCE ENCE ER IC ABLE IBLE ANT EMENT MENT ENT ISM ATE ITI OUS IVE IZE
+//         ->  delete
+//    ION  ->  delete if preceded by S or T
+//
+var porter2Step4Rules = []porter2Rule{
+	{"ement", ""},
+	{"able", ""},
+	{"ance", ""},
+	{"ence", ""},
+	{"ible", ""},
+	{"ment", ""},
+	{"ant", ""},
+	{"ate", ""},
+	{"ent", ""},
+	{"ion", ""},
+	{"ism", ""},
+	{"iti", ""},
+	{"ive", ""},
+	{"ize", ""},
+	{"ous", ""},
+	{"al", ""},
+	{"er", ""},
+	{"ic", ""},
+}
+
+func porter2Step4(word []byte, r2 int) []byte {
+	rule, ok := porter2Match(word, porter2Step4Rules)
+	if !ok {
+		return word
+	}
+	stem := word[:len(word)-len(rule.suffix)]
+	if len(stem) < r2 {
+		return word
+	}
+	if rule.suffix == "ion" && !bytes.HasSuffix(stem, []byte("s")) && !bytes.HasSuffix(stem, []byte("t")) {
+		return word
+	}
+	return stem
+}
+
+//
+// Step 5
+//
+//    E  ->  delete if in R2, or in R1 and not preceded by a short syllable
+//    L  ->  delete if in R2 and preceded by L
+//
+func porter2Step5(word []byte, r1, r2 int) []byte {
+	l := len(word)
+	if bytes.HasSuffix(word, []byte("e")) {
+		if l-1 >= r2 || (l-1 >= r1 && !porter2ShortSyllable(word[:l-1])) {
+			return word[:l-1]
+		}
+	} else if bytes.HasSuffix(word, []byte("l")) {
+		if l-1 >= r2 && bytes.HasSuffix(word[:l-1], []byte("l")) {
+			return word[:l-1]
+		}
+	}
+	return word
+}
+
+//
+// porter2Match returns the rule with the longest suffix of word. The rules
+// are listed longest suffix first, so the first hit is the longest one.
+//
+func porter2Match(word []byte, rules []porter2Rule) (porter2Rule, bool) {
+	for _, rule := range rules {
+		if len(word) >= len(rule.suffix) && string(word[len(word)-len(rule.suffix):]) == rule.suffix {
+			return rule, true
+		}
+	}
+	return porter2Rule{}, false
+}
+
+//
+// StemPorter2 returns the stem of word according to the Porter2 (Snowball
+// English) algorithm. Like Snowball, only the ASCII vowels are vowels.
+//
+func StemPorter2(word []byte) []byte {
+	word = bytes.TrimSpace(appendLower(make([]byte, 0, len(word)), word))
+	if len(word) < 3 {
+		return word
+	}
+	if stem, ok := porter2Exceptions[string(word)]; ok {
+		return append(word[:0], stem...)
+	}
+	if word[0] == '\'' {
+		word = word[1:]
+	}
+	porter2MarkY(word)
+	r1, r2 := porter2Regions(word)
+
+	word = porter2Step0(word)
+	word = porter2Step1a(word)
+	if !porter2Invariants[string(word)] {
+		word = porter2Step1b(word, r1)
+		word = porter2Step1c(word)
+		word = porter2Step2(word, r1)
+		word = porter2Step3(word, r1, r2)
+		word = porter2Step4(word, r2)
+		word = porter2Step5(word, r1, r2)
+	}
+
+	for i, b := range word {
+		if b == 'Y' {
+			word[i] = 'y'
+		}
+	}
+	return word
+}
@@ -0,0 +1,135 @@
+package stemmer
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestPorter2Step1a(t *testing.T) {
+	fixtures := []word{
+		[]byte("caresses"),
+		[]byte("cries"),
+		[]byte("ties"),
+		[]byte("gas"),
+		[]byte("gaps"),
+		[]byte("kiwis"),
+		[]byte("bus"),
+	}
+
+	stemmed := []word{
+		[]byte("caress"),
+		[]byte("cri"),
+		[]byte("tie"),
+		[]byte("gas"),
+		[]byte("gap"),
+		[]byte("kiwi"),
+		[]byte("bus"),
+	}
+
+	for k, value := range fixtures {
+		if result := porter2Step1a(value); !bytes.Equal(result, stemmed[k]) {
+			t.Errorf("porter2Step1a() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, stemmed[k])
+		}
+	}
+}
+
+func TestPorter2Regions(t *testing.T) {
+	fixtures := []word{
+		[]byte("beautiful"),
+		[]byte("beauty"),
+		[]byte("beau"),
+		[]byte("animadversion"),
+		[]byte("sprinkled"),
+		[]byte("eucharist"),
+		[]byte("generous"),
+		[]byte("communism"),
+	}
+
+	regions := [][2]int{
+		{5, 7},
+		{5, 6},
+		{4, 4},
+		{2, 4},
+		{5, 9},
+		{3, 6},
+		{5, 8},
+		{6, 8},
+	}
+
+	for k, value := range fixtures {
+		if r1, r2 := porter2Regions(value); r1 != regions[k][0] || r2 != regions[k][1] {
+			t.Errorf("porter2Regions() return value not what was expected, pass: '%s' return: '%d %d' expected: '%d %d'", value, r1, r2, regions[k][0], regions[k][1])
+		}
+	}
+}
+
+func TestStemPorter2(t *testing.T) {
+	fixtures := []word{
+		[]byte("skies"),
+		[]byte("dying"),
+		[]byte("news"),
+		[]byte("inning"),
+		[]byte("generously"),
+		[]byte("'tis"),
+		[]byte("jim's"),
+		[]byte("hoping"),
+		[]byte("hopping"),
+		[]byte("youth"),
+		[]byte("at"),
+	}
+
+	stemmed := []word{
+		[]byte("sky"),
+		[]byte("die"),
+		[]byte("news"),
+		[]byte("inning"),
+		[]byte("generous"),
+		[]byte("tis"),
+		[]byte("jim"),
+		[]byte("hope"),
+		[]byte("hop"),
+		[]byte("youth"),
+		[]byte("at"),
+	}
+
+	for k, value := range fixtures {
+		if result := StemPorter2(value); !bytes.Equal(result, stemmed[k]) {
+			t.Errorf("StemPorter2() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, stemmed[k])
+		}
+	}
+}
+
+func TestPorter2Vocal(t *testing.T) {
+	v, err := os.Open("testdata/porter2/voc.txt")
+	if err != nil {
+		panic(err)
+	}
+	defer v.Close()
+	vocScanner := bufio.NewScanner(v)
+
+	o, err := os.Open("testdata/porter2/output.txt")
+	if err != nil {
+		panic(err)
+	}
+	defer o.Close()
+	outScanner := bufio.NewScanner(o)
+
+	for vocScanner.Scan() {
+		outScanner.Scan()
+		word := vocScanner.Bytes()
+		stem := outScanner.Bytes()
+
+		if result := StemPorter2(word); !bytes.Equal(result, stem) {
+			t.Errorf("StemPorter2() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", word, result, stem)
+		}
+	}
+}
+
+func BenchmarkStemPorter2(b *testing.B) {
+	word := []byte("troubles")
+	for n := 0; n < b.N; n++ {
+		StemPorter2(word)
+	}
+}
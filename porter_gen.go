@@ -0,0 +1,600 @@
+// Code generated by stemgen from rules/porter.json. DO NOT EDIT.
+
+package stemmer
+
+// porterRun runs the steps of porter on word, which must be lower case.
+func porterRun(word []byte) []byte {
+	if len(word) < 3 {
+		return word
+	}
+	word = porterStep1a(word)
+	word = porterStep1b(word)
+	word = porterStep1c(word)
+	word = porterStep2(word)
+	word = porterStep3(word)
+	word = porterStep4(word)
+	word = porterStep5a(word)
+	word = porterStep5b(word)
+	return word
+}
+
+// porterStep1a runs step 1a.
+func porterStep1a(word []byte) []byte {
+	if len(word) > 0 {
+		switch word[len(word)-1] {
+		case 's':
+			if hasSuffix(word, "sses") {
+				return word[:len(word)-2]
+			}
+			if hasSuffix(word, "ies") {
+				return word[:len(word)-2]
+			}
+			if hasSuffix(word, "ss") {
+				return word
+			}
+			return word[:len(word)-1]
+		}
+	}
+	return word
+}
+
+// porterStep1b runs step 1b.
+func porterStep1b(word []byte) []byte {
+	if len(word) > 0 {
+		switch word[len(word)-1] {
+		case 'd':
+			if hasSuffix(word, "eed") {
+				stem := word[:len(word)-3]
+				if measure(stem) > 0 {
+					return word[:len(word)-1]
+				}
+				return word
+			}
+			if hasSuffix(word, "ed") {
+				stem := word[:len(word)-2]
+				if containVowel(stem) {
+					return porterStep1b2(stem)
+				}
+				return word
+			}
+		case 'g':
+			if hasSuffix(word, "ing") {
+				stem := word[:len(word)-3]
+				if containVowel(stem) {
+					return porterStep1b2(stem)
+				}
+				return word
+			}
+		}
+	}
+	return word
+}
+
+// porterStep1c runs step 1c.
+func porterStep1c(word []byte) []byte {
+	if len(word) > 0 {
+		switch word[len(word)-1] {
+		case 'y':
+			stem := word[:len(word)-1]
+			if containVowel(stem) {
+				return append(stem, "i"...)
+			}
+			return word
+		}
+	}
+	return word
+}
+
+// porterStep2 runs step 2.
+func porterStep2(word []byte) []byte {
+	if len(word) > 0 {
+		switch word[len(word)-1] {
+		case 'i':
+			if hasSuffix(word, "biliti") {
+				stem := word[:len(word)-6]
+				if measure(stem) > 0 {
+					return append(word[:len(word)-5], "le"...)
+				}
+				return word
+			}
+			if hasSuffix(word, "entli") {
+				stem := word[:len(word)-5]
+				if measure(stem) > 0 {
+					return word[:len(word)-2]
+				}
+				return word
+			}
+			if hasSuffix(word, "ousli") {
+				stem := word[:len(word)-5]
+				if measure(stem) > 0 {
+					return word[:len(word)-2]
+				}
+				return word
+			}
+			if hasSuffix(word, "aliti") {
+				stem := word[:len(word)-5]
+				if measure(stem) > 0 {
+					return word[:len(word)-3]
+				}
+				return word
+			}
+			if hasSuffix(word, "iviti") {
+				stem := word[:len(word)-5]
+				if measure(stem) > 0 {
+					return append(word[:len(word)-3], "e"...)
+				}
+				return word
+			}
+			if hasSuffix(word, "enci") {
+				stem := word[:len(word)-4]
+				if measure(stem) > 0 {
+					return append(word[:len(word)-1], "e"...)
+				}
+				return word
+			}
+			if hasSuffix(word, "anci") {
+				stem := word[:len(word)-4]
+				if measure(stem) > 0 {
+					return append(word[:len(word)-1], "e"...)
+				}
+				return word
+			}
+			if hasSuffix(word, "abli") {
+				stem := word[:len(word)-4]
+				if measure(stem) > 0 {
+					return append(word[:len(word)-1], "e"...)
+				}
+				return word
+			}
+			if hasSuffix(word, "alli") {
+				stem := word[:len(word)-4]
+				if measure(stem) > 0 {
+					return word[:len(word)-2]
+				}
+				return word
+			}
+			if hasSuffix(word, "logi") {
+				stem := word[:len(word)-4]
+				if measure(stem) > 0 {
+					return word[:len(word)-1]
+				}
+				return word
+			}
+			if hasSuffix(word, "bli") {
+				stem := word[:len(word)-3]
+				if measure(stem) > 0 {
+					return append(word[:len(word)-1], "e"...)
+				}
+				return word
+			}
+			if hasSuffix(word, "eli") {
+				stem := word[:len(word)-3]
+				if measure(stem) > 0 {
+					return word[:len(word)-2]
+				}
+				return word
+			}
+		case 'l':
+			if hasSuffix(word, "ational") {
+				stem := word[:len(word)-7]
+				if measure(stem) > 0 {
+					return append(word[:len(word)-5], "e"...)
+				}
+				return word
+			}
+			if hasSuffix(word, "tional") {
+				stem := word[:len(word)-6]
+				if measure(stem) > 0 {
+					return word[:len(word)-2]
+				}
+				return word
+			}
+		case 'm':
+			if hasSuffix(word, "alism") {
+				stem := word[:len(word)-5]
+				if measure(stem) > 0 {
+					return word[:len(word)-3]
+				}
+				return word
+			}
+		case 'n':
+			if hasSuffix(word, "ization") {
+				stem := word[:len(word)-7]
+				if measure(stem) > 0 {
+					return append(word[:len(word)-5], "e"...)
+				}
+				return word
+			}
+			if hasSuffix(word, "ation") {
+				stem := word[:len(word)-5]
+				if measure(stem) > 0 {
+					return append(word[:len(word)-3], "e"...)
+				}
+				return word
+			}
+		case 'r':
+			if hasSuffix(word, "izer") {
+				stem := word[:len(word)-4]
+				if measure(stem) > 0 {
+					return word[:len(word)-1]
+				}
+				return word
+			}
+			if hasSuffix(word, "ator") {
+				stem := word[:len(word)-4]
+				if measure(stem) > 0 {
+					return append(word[:len(word)-2], "e"...)
+				}
+				return word
+			}
+		case 's':
+			if hasSuffix(word, "iveness") {
+				stem := word[:len(word)-7]
+				if measure(stem) > 0 {
+					return word[:len(word)-4]
+				}
+				return word
+			}
+			if hasSuffix(word, "fulness") {
+				stem := word[:len(word)-7]
+				if measure(stem) > 0 {
+					return word[:len(word)-4]
+				}
+				return word
+			}
+			if hasSuffix(word, "ousness") {
+				stem := word[:len(word)-7]
+				if measure(stem) > 0 {
+					return word[:len(word)-4]
+				}
+				return word
+			}
+		}
+	}
+	return word
+}
+
+// porterStep3 runs step 3.
+func porterStep3(word []byte) []byte {
+	if len(word) > 0 {
+		switch word[len(word)-1] {
+		case 'e':
+			if hasSuffix(word, "icate") {
+				stem := word[:len(word)-5]
+				if measure(stem) > 0 {
+					return word[:len(word)-3]
+				}
+				return word
+			}
+			if hasSuffix(word, "ative") {
+				stem := word[:len(word)-5]
+				if measure(stem) > 0 {
+					return stem
+				}
+				return word
+			}
+			if hasSuffix(word, "alize") {
+				stem := word[:len(word)-5]
+				if measure(stem) > 0 {
+					return word[:len(word)-3]
+				}
+				return word
+			}
+		case 'i':
+			if hasSuffix(word, "iciti") {
+				stem := word[:len(word)-5]
+				if measure(stem) > 0 {
+					return word[:len(word)-3]
+				}
+				return word
+			}
+		case 'l':
+			if hasSuffix(word, "ical") {
+				stem := word[:len(word)-4]
+				if measure(stem) > 0 {
+					return word[:len(word)-2]
+				}
+				return word
+			}
+			if hasSuffix(word, "ful") {
+				stem := word[:len(word)-3]
+				if measure(stem) > 0 {
+					return stem
+				}
+				return word
+			}
+		case 's':
+			if hasSuffix(word, "ness") {
+				stem := word[:len(word)-4]
+				if measure(stem) > 0 {
+					return stem
+				}
+				return word
+			}
+		}
+	}
+	return word
+}
+
+// porterStep4 runs step 4.
+func porterStep4(word []byte) []byte {
+	if len(word) > 0 {
+		switch word[len(word)-1] {
+		case 'c':
+			if hasSuffix(word, "ic") {
+				stem := word[:len(word)-2]
+				if measure(stem) > 1 {
+					return stem
+				}
+				return word
+			}
+		case 'e':
+			if hasSuffix(word, "ance") {
+				stem := word[:len(word)-4]
+				if measure(stem) > 1 {
+					return stem
+				}
+				return word
+			}
+			if hasSuffix(word, "ence") {
+				stem := word[:len(word)-4]
+				if measure(stem) > 1 {
+					return stem
+				}
+				return word
+			}
+			if hasSuffix(word, "able") {
+				stem := word[:len(word)-4]
+				if measure(stem) > 1 {
+					return stem
+				}
+				return word
+			}
+			if hasSuffix(word, "ible") {
+				stem := word[:len(word)-4]
+				if measure(stem) > 1 {
+					return stem
+				}
+				return word
+			}
+			if hasSuffix(word, "ate") {
+				stem := word[:len(word)-3]
+				if measure(stem) > 1 {
+					return stem
+				}
+				return word
+			}
+			if hasSuffix(word, "ive") {
+				stem := word[:len(word)-3]
+				if measure(stem) > 1 {
+					return stem
+				}
+				return word
+			}
+			if hasSuffix(word, "ize") {
+				stem := word[:len(word)-3]
+				if measure(stem) > 1 {
+					return stem
+				}
+				return word
+			}
+		case 'i':
+			if hasSuffix(word, "iti") {
+				stem := word[:len(word)-3]
+				if measure(stem) > 1 {
+					return stem
+				}
+				return word
+			}
+		case 'l':
+			if hasSuffix(word, "al") {
+				stem := word[:len(word)-2]
+				if measure(stem) > 1 {
+					return stem
+				}
+				return word
+			}
+		case 'm':
+			if hasSuffix(word, "ism") {
+				stem := word[:len(word)-3]
+				if measure(stem) > 1 {
+					return stem
+				}
+				return word
+			}
+		case 'n':
+			if hasSuffix(word, "ion") {
+				stem := word[:len(word)-3]
+				if measure(stem) > 1 && (endsWithAny(stem, "s") || endsWithAny(stem, "t")) {
+					return stem
+				}
+				return word
+			}
+		case 'r':
+			if hasSuffix(word, "er") {
+				stem := word[:len(word)-2]
+				if measure(stem) > 1 {
+					return stem
+				}
+				return word
+			}
+		case 's':
+			if hasSuffix(word, "ous") {
+				stem := word[:len(word)-3]
+				if measure(stem) > 1 {
+					return stem
+				}
+				return word
+			}
+		case 't':
+			if hasSuffix(word, "ement") {
+				stem := word[:len(word)-5]
+				if measure(stem) > 1 {
+					return stem
+				}
+				return word
+			}
+			if hasSuffix(word, "ment") {
+				stem := word[:len(word)-4]
+				if measure(stem) > 1 {
+					return stem
+				}
+				return word
+			}
+			if hasSuffix(word, "ant") {
+				stem := word[:len(word)-3]
+				if measure(stem) > 1 {
+					return stem
+				}
+				return word
+			}
+			if hasSuffix(word, "ent") {
+				stem := word[:len(word)-3]
+				if measure(stem) > 1 {
+					return stem
+				}
+				return word
+			}
+		case 'u':
+			if hasSuffix(word, "ou") {
+				stem := word[:len(word)-2]
+				if measure(stem) > 1 {
+					return stem
+				}
+				return word
+			}
+		}
+	}
+	return word
+}
+
+// porterStep5a runs step 5a.
+func porterStep5a(word []byte) []byte {
+	if len(word) > 0 {
+		switch word[len(word)-1] {
+		case 'e':
+			stem := word[:len(word)-1]
+			if m := measure(stem); m > 1 || m == 1 && !isCVCSuffix(stem) {
+				return stem
+			}
+			return word
+		}
+	}
+	return word
+}
+
+// porterStep5b runs step 5b.
+func porterStep5b(word []byte) []byte {
+	if len(word) > 0 {
+		switch word[len(word)-1] {
+		case 'l':
+			stem := word[:len(word)-1]
+			if measure(stem) > 1 && endsWithAny(stem, "l") {
+				return stem
+			}
+			return word
+		}
+	}
+	return word
+}
+
+// porterStep1b2 runs step 1b2.
+func porterStep1b2(word []byte) []byte {
+	if len(word) > 0 {
+		switch word[len(word)-1] {
+		case 'b':
+			if hasSuffix(word, "bb") {
+				return word[:len(word)-1]
+			}
+		case 'c':
+			if hasSuffix(word, "cc") {
+				return word[:len(word)-1]
+			}
+		case 'd':
+			if hasSuffix(word, "dd") {
+				return word[:len(word)-1]
+			}
+		case 'f':
+			if hasSuffix(word, "ff") {
+				return word[:len(word)-1]
+			}
+		case 'g':
+			if hasSuffix(word, "gg") {
+				return word[:len(word)-1]
+			}
+		case 'h':
+			if hasSuffix(word, "hh") {
+				return word[:len(word)-1]
+			}
+		case 'j':
+			if hasSuffix(word, "jj") {
+				return word[:len(word)-1]
+			}
+		case 'k':
+			if hasSuffix(word, "kk") {
+				return word[:len(word)-1]
+			}
+		case 'l':
+			if hasSuffix(word, "bl") {
+				return append(word, "e"...)
+			}
+			if hasSuffix(word, "ll") {
+				return word
+			}
+		case 'm':
+			if hasSuffix(word, "mm") {
+				return word[:len(word)-1]
+			}
+		case 'n':
+			if hasSuffix(word, "nn") {
+				return word[:len(word)-1]
+			}
+		case 'p':
+			if hasSuffix(word, "pp") {
+				return word[:len(word)-1]
+			}
+		case 'q':
+			if hasSuffix(word, "qq") {
+				return word[:len(word)-1]
+			}
+		case 'r':
+			if hasSuffix(word, "rr") {
+				return word[:len(word)-1]
+			}
+		case 's':
+			if hasSuffix(word, "ss") {
+				return word
+			}
+		case 't':
+			if hasSuffix(word, "at") {
+				return append(word, "e"...)
+			}
+			if hasSuffix(word, "tt") {
+				return word[:len(word)-1]
+			}
+		case 'v':
+			if hasSuffix(word, "vv") {
+				return word[:len(word)-1]
+			}
+		case 'w':
+			if hasSuffix(word, "ww") {
+				return word[:len(word)-1]
+			}
+		case 'x':
+			if hasSuffix(word, "xx") {
+				return word[:len(word)-1]
+			}
+		case 'z':
+			if hasSuffix(word, "iz") {
+				return append(word, "e"...)
+			}
+			if hasSuffix(word, "zz") {
+				return word
+			}
+		}
+	}
+	if measure(word) == 1 && isCVCSuffix(word) {
+		return append(word, "e"...)
+	}
+	return word
+}
@@ -0,0 +1,163 @@
+package stemmer
+
+import "strings"
+
+//
+// The Portuguese stemmer:
+//
+//    https://snowballstem.org/algorithms/portuguese/stemmer.html
+//
+// The nasalised vowels ã and õ are spelled a~ and o~ while stemming, so that
+// the ~ counts as a consonant.
+//
+
+const portugueseVowels = "aeiouáéíóúâêô"
+
+var (
+	portugueseStep1Suffixes = newSuffixList(
+		"eza", "ezas", "ico", "ica", "icos", "icas", "ismo", "ismos", "ável",
+		"ível", "ista", "istas", "oso", "osa", "osos", "osas", "amento",
+		"amentos", "imento", "imentos", "adora", "ador", "aça~o", "adoras",
+		"adores", "aço~es", "ante", "antes", "ância",
+		"logia", "logias",
+		"uça~o", "uço~es",
+		"ência", "ências",
+		"amente",
+		"mente",
+		"idade", "idades",
+		"iva", "ivo", "ivas", "ivos",
+		"ira", "iras",
+	)
+	portugueseVerbSuffixes = newSuffixList(
+		"ada", "ida", "ia", "aria", "eria", "iria", "ara", "era", "ira", "ava",
+		"asse", "esse", "isse", "aste", "este", "iste", "ei", "arei", "erei",
+		"irei", "am", "iam", "ariam", "eriam", "iriam", "aram", "eram", "iram",
+		"avam", "em", "arem", "erem", "irem", "assem", "essem", "issem", "ado",
+		"ido", "ando", "endo", "indo", "ara~o", "era~o", "ira~o", "ar", "er",
+		"ir", "as", "adas", "idas", "ias", "arias", "erias", "irias", "aras",
+		"eras", "iras", "avas", "es", "ardes", "erdes", "irdes", "ares", "eres",
+		"ires", "asses", "esses", "isses", "astes", "estes", "istes", "is",
+		"ais", "eis", "areis", "ereis", "ireis", "áreis", "éreis", "íreis",
+		"ásseis", "ésseis", "ísseis", "áveis", "íeis", "aríeis", "eríeis",
+		"iríeis", "ados", "idos", "amos", "áramos", "éramos", "íramos", "ávamos",
+		"íamos", "aríamos", "eríamos", "iríamos", "emos", "aremos", "eremos",
+		"iremos", "ássemos", "êssemos", "íssemos", "imos", "armos", "ermos",
+		"irmos", "ámos", "arás", "erás", "irás", "eu", "iu", "ou", "ará", "erá",
+		"irá",
+	)
+	portugueseResidualSuffixes = newSuffixList("os", "a", "i", "o", "á", "í", "ó")
+)
+
+func stemPortuguese(w []rune) []rune {
+	w = []rune(strings.NewReplacer("ã", "a~", "õ", "o~").Replace(string(w)))
+	rv := romanceRV(w, portugueseVowels)
+	r1 := regionAfter(w, 0, portugueseVowels)
+	r2 := regionAfter(w, r1, portugueseVowels)
+
+	// Step 1 removes the standard suffixes, and only if it does not, step 2
+	// the verb suffixes. If either changed the word, step 3 removes an i
+	// after c in RV; otherwise step 4 removes a residual suffix in RV.
+	var ok bool
+	if w, ok = portugueseStandardSuffix(w, rv, r1, r2); !ok {
+		if s, start := portugueseVerbSuffixes.find(w, rv); s != "" {
+			w, ok = w[:start], true
+		}
+	}
+	if ok {
+		if i := suffixStart(w, "i"); i >= rv && inGroup(w, i-1, "c") {
+			w = w[:i]
+		}
+	} else if s, start := portugueseResidualSuffixes.find(w, 0); s != "" && start >= rv {
+		w = w[:start]
+	}
+
+	// Step 5: e, é or ê in RV is removed, and with it the u of gu or the i
+	// of ci in RV; ç becomes c.
+	if n := len(w); n > 0 {
+		switch w[n-1] {
+		case 'e', 'é', 'ê':
+			if n-1 >= rv {
+				w = w[:n-1]
+				if n := len(w); n-1 >= rv && (endsWith(w, "gu") || endsWith(w, "ci")) {
+					w = w[:n-1]
+				}
+			}
+		case 'ç':
+			w[n-1] = 'c'
+		}
+	}
+
+	return []rune(strings.NewReplacer("a~", "ã", "o~", "õ").Replace(string(w)))
+}
+
+//
+// portugueseStandardSuffix is step 1, and reports whether it changed w.
+//
+func portugueseStandardSuffix(w []rune, rv, r1, r2 int) ([]rune, bool) {
+	s, start := portugueseStep1Suffixes.find(w, 0)
+	switch s {
+	case "":
+		return w, false
+	case "amente":
+		if start < r1 {
+			return w, false
+		}
+	case "ira", "iras":
+		if start < rv || !inGroup(w, start-1, "e") {
+			return w, false
+		}
+	default:
+		if start < r2 {
+			return w, false
+		}
+	}
+
+	// removeInR2 removes each of the suffixes in turn from the end of w
+	// while it is in R2.
+	removeInR2 := func(suffixes ...string) {
+		for _, s := range suffixes {
+			start := suffixStart(w, s)
+			if start < r2 {
+				return
+			}
+			w = w[:start]
+		}
+	}
+
+	switch s {
+	case "logia", "logias":
+		w = replaceSuffix(w, start, "log")
+	case "uça~o", "uço~es":
+		w = replaceSuffix(w, start, "u")
+	case "ência", "ências":
+		w = replaceSuffix(w, start, "ente")
+	case "amente":
+		w = w[:start]
+		switch s, start := newSuffixList("iv", "os", "ic", "ad").find(w, 0); s {
+		case "iv":
+			removeInR2("iv", "at")
+		case "os", "ic", "ad":
+			if start >= r2 {
+				w = w[:start]
+			}
+		}
+	case "mente":
+		w = w[:start]
+		if s, _ := newSuffixList("ante", "avel", "ível").find(w, 0); s != "" {
+			removeInR2(s)
+		}
+	case "idade", "idades":
+		w = w[:start]
+		if s, _ := newSuffixList("abil", "ic", "iv").find(w, 0); s != "" {
+			removeInR2(s)
+		}
+	case "iva", "ivo", "ivas", "ivos":
+		w = w[:start]
+		removeInR2("at")
+	case "ira", "iras":
+		w = replaceSuffix(w, start, "ir")
+	default:
+		w = w[:start]
+	}
+	return w, true
+}
@@ -0,0 +1,489 @@
+// The stemming service of stemd, for clients that only speak gRPC. It
+// mirrors the HTTP API of cmd/stemd: Stem and StemBatch run a registered
+// stemmer (stemmer.Lookup), Analyze runs the English analysis chain of
+// package analysis on each text it receives.
+//
+// The Go code in stemmerpb is generated from this file with protoc-gen-go
+// and protoc-gen-go-grpc:
+//
+//   protoc --go_out=stemmerpb --go_opt=paths=source_relative \
+//       --go-grpc_out=stemmerpb --go-grpc_opt=paths=source_relative \
+//       stemmer.proto
+//
+// cmd/stemrpc serves the service and package client calls it.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
+// source: stemmer.proto
+
+package stemmerpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StemRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Word  string                 `protobuf:"bytes,1,opt,name=word,proto3" json:"word,omitempty"`
+	// algorithm is the name a stemmer is registered under, "porter" if empty.
+	Algorithm     string `protobuf:"bytes,2,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StemRequest) Reset() {
+	*x = StemRequest{}
+	mi := &file_stemmer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StemRequest) ProtoMessage() {}
+
+func (x *StemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stemmer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StemRequest.ProtoReflect.Descriptor instead.
+func (*StemRequest) Descriptor() ([]byte, []int) {
+	return file_stemmer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StemRequest) GetWord() string {
+	if x != nil {
+		return x.Word
+	}
+	return ""
+}
+
+func (x *StemRequest) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+type StemResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stem          string                 `protobuf:"bytes,1,opt,name=stem,proto3" json:"stem,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StemResponse) Reset() {
+	*x = StemResponse{}
+	mi := &file_stemmer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StemResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StemResponse) ProtoMessage() {}
+
+func (x *StemResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stemmer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StemResponse.ProtoReflect.Descriptor instead.
+func (*StemResponse) Descriptor() ([]byte, []int) {
+	return file_stemmer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StemResponse) GetStem() string {
+	if x != nil {
+		return x.Stem
+	}
+	return ""
+}
+
+type StemBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Words         []string               `protobuf:"bytes,1,rep,name=words,proto3" json:"words,omitempty"`
+	Algorithm     string                 `protobuf:"bytes,2,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StemBatchRequest) Reset() {
+	*x = StemBatchRequest{}
+	mi := &file_stemmer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StemBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StemBatchRequest) ProtoMessage() {}
+
+func (x *StemBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stemmer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StemBatchRequest.ProtoReflect.Descriptor instead.
+func (*StemBatchRequest) Descriptor() ([]byte, []int) {
+	return file_stemmer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StemBatchRequest) GetWords() []string {
+	if x != nil {
+		return x.Words
+	}
+	return nil
+}
+
+func (x *StemBatchRequest) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+type StemBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stems         []string               `protobuf:"bytes,1,rep,name=stems,proto3" json:"stems,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StemBatchResponse) Reset() {
+	*x = StemBatchResponse{}
+	mi := &file_stemmer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StemBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StemBatchResponse) ProtoMessage() {}
+
+func (x *StemBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stemmer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StemBatchResponse.ProtoReflect.Descriptor instead.
+func (*StemBatchResponse) Descriptor() ([]byte, []int) {
+	return file_stemmer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StemBatchResponse) GetStems() []string {
+	if x != nil {
+		return x.Stems
+	}
+	return nil
+}
+
+type AnalyzeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeRequest) Reset() {
+	*x = AnalyzeRequest{}
+	mi := &file_stemmer_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeRequest) ProtoMessage() {}
+
+func (x *AnalyzeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stemmer_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeRequest.ProtoReflect.Descriptor instead.
+func (*AnalyzeRequest) Descriptor() ([]byte, []int) {
+	return file_stemmer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AnalyzeRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type AnalyzeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tokens        []*Token               `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeResponse) Reset() {
+	*x = AnalyzeResponse{}
+	mi := &file_stemmer_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeResponse) ProtoMessage() {}
+
+func (x *AnalyzeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stemmer_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeResponse.ProtoReflect.Descriptor instead.
+func (*AnalyzeResponse) Descriptor() ([]byte, []int) {
+	return file_stemmer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AnalyzeResponse) GetTokens() []*Token {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+// Token is an analysis.Token.
+type Token struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Term  string                 `protobuf:"bytes,1,opt,name=term,proto3" json:"term,omitempty"`
+	// start and end are the byte offsets of the token in the text.
+	Start             int32 `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	End               int32 `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`
+	PositionIncrement int32 `protobuf:"varint,4,opt,name=position_increment,json=positionIncrement,proto3" json:"position_increment,omitempty"`
+	// type is the tokenizer.Type of the token: word, number, punctuation,
+	// url, email or hashtag.
+	Type          string `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Token) Reset() {
+	*x = Token{}
+	mi := &file_stemmer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Token) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Token) ProtoMessage() {}
+
+func (x *Token) ProtoReflect() protoreflect.Message {
+	mi := &file_stemmer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Token.ProtoReflect.Descriptor instead.
+func (*Token) Descriptor() ([]byte, []int) {
+	return file_stemmer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Token) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *Token) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *Token) GetEnd() int32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *Token) GetPositionIncrement() int32 {
+	if x != nil {
+		return x.PositionIncrement
+	}
+	return 0
+}
+
+func (x *Token) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+var File_stemmer_proto protoreflect.FileDescriptor
+
+const file_stemmer_proto_rawDesc = "" +
+	"\n" +
+	"\rstemmer.proto\x12\n" +
+	"stemmer.v1\"?\n" +
+	"\vStemRequest\x12\x12\n" +
+	"\x04word\x18\x01 \x01(\tR\x04word\x12\x1c\n" +
+	"\talgorithm\x18\x02 \x01(\tR\talgorithm\"\"\n" +
+	"\fStemResponse\x12\x12\n" +
+	"\x04stem\x18\x01 \x01(\tR\x04stem\"F\n" +
+	"\x10StemBatchRequest\x12\x14\n" +
+	"\x05words\x18\x01 \x03(\tR\x05words\x12\x1c\n" +
+	"\talgorithm\x18\x02 \x01(\tR\talgorithm\")\n" +
+	"\x11StemBatchResponse\x12\x14\n" +
+	"\x05stems\x18\x01 \x03(\tR\x05stems\"$\n" +
+	"\x0eAnalyzeRequest\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\"<\n" +
+	"\x0fAnalyzeResponse\x12)\n" +
+	"\x06tokens\x18\x01 \x03(\v2\x11.stemmer.v1.TokenR\x06tokens\"\x86\x01\n" +
+	"\x05Token\x12\x12\n" +
+	"\x04term\x18\x01 \x01(\tR\x04term\x12\x14\n" +
+	"\x05start\x18\x02 \x01(\x05R\x05start\x12\x10\n" +
+	"\x03end\x18\x03 \x01(\x05R\x03end\x12-\n" +
+	"\x12position_increment\x18\x04 \x01(\x05R\x11positionIncrement\x12\x12\n" +
+	"\x04type\x18\x05 \x01(\tR\x04type2\xd6\x01\n" +
+	"\aStemmer\x129\n" +
+	"\x04Stem\x12\x17.stemmer.v1.StemRequest\x1a\x18.stemmer.v1.StemResponse\x12H\n" +
+	"\tStemBatch\x12\x1c.stemmer.v1.StemBatchRequest\x1a\x1d.stemmer.v1.StemBatchResponse\x12F\n" +
+	"\aAnalyze\x12\x1a.stemmer.v1.AnalyzeRequest\x1a\x1b.stemmer.v1.AnalyzeResponse(\x010\x01B.Z,github.com/pigi72333/stemmer/proto/stemmerpbb\x06proto3"
+
+var (
+	file_stemmer_proto_rawDescOnce sync.Once
+	file_stemmer_proto_rawDescData []byte
+)
+
+func file_stemmer_proto_rawDescGZIP() []byte {
+	file_stemmer_proto_rawDescOnce.Do(func() {
+		file_stemmer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_stemmer_proto_rawDesc), len(file_stemmer_proto_rawDesc)))
+	})
+	return file_stemmer_proto_rawDescData
+}
+
+var file_stemmer_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_stemmer_proto_goTypes = []any{
+	(*StemRequest)(nil),       // 0: stemmer.v1.StemRequest
+	(*StemResponse)(nil),      // 1: stemmer.v1.StemResponse
+	(*StemBatchRequest)(nil),  // 2: stemmer.v1.StemBatchRequest
+	(*StemBatchResponse)(nil), // 3: stemmer.v1.StemBatchResponse
+	(*AnalyzeRequest)(nil),    // 4: stemmer.v1.AnalyzeRequest
+	(*AnalyzeResponse)(nil),   // 5: stemmer.v1.AnalyzeResponse
+	(*Token)(nil),             // 6: stemmer.v1.Token
+}
+var file_stemmer_proto_depIdxs = []int32{
+	6, // 0: stemmer.v1.AnalyzeResponse.tokens:type_name -> stemmer.v1.Token
+	0, // 1: stemmer.v1.Stemmer.Stem:input_type -> stemmer.v1.StemRequest
+	2, // 2: stemmer.v1.Stemmer.StemBatch:input_type -> stemmer.v1.StemBatchRequest
+	4, // 3: stemmer.v1.Stemmer.Analyze:input_type -> stemmer.v1.AnalyzeRequest
+	1, // 4: stemmer.v1.Stemmer.Stem:output_type -> stemmer.v1.StemResponse
+	3, // 5: stemmer.v1.Stemmer.StemBatch:output_type -> stemmer.v1.StemBatchResponse
+	5, // 6: stemmer.v1.Stemmer.Analyze:output_type -> stemmer.v1.AnalyzeResponse
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_stemmer_proto_init() }
+func file_stemmer_proto_init() {
+	if File_stemmer_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_stemmer_proto_rawDesc), len(file_stemmer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_stemmer_proto_goTypes,
+		DependencyIndexes: file_stemmer_proto_depIdxs,
+		MessageInfos:      file_stemmer_proto_msgTypes,
+	}.Build()
+	File_stemmer_proto = out.File
+	file_stemmer_proto_goTypes = nil
+	file_stemmer_proto_depIdxs = nil
+}
@@ -0,0 +1,161 @@
+package stemmer
+
+import (
+	"sort"
+	"sync"
+)
+
+//
+// Stemmer is implemented by every algorithm of the package, so that callers
+// can pick one by name and use them interchangeably.
+//
+type Stemmer interface {
+	// Stem returns the stem of word. word is never modified.
+	Stem(word []byte) []byte
+	// StemString returns the stem of word.
+	StemString(word string) string
+	// Name returns the name the stemmer is registered under.
+	Name() string
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Stemmer)
+)
+
+func init() {
+	Register("porter", Porter{})
+	Register("porter2", Porter2{})
+	Register("lovins", Lovins{})
+	Register("lancaster", lancasterStandard)
+	Register("s", sStemmerRules)
+	Register("plural", minimalPluralRules)
+	Register("krovetz", &Krovetz{})
+	for _, l := range languages {
+		Register(l.code, l)
+	}
+}
+
+//
+// Register makes a stemmer available by name. It panics if s is nil or if
+// Register is called twice with the same name.
+//
+func Register(name string, s Stemmer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if s == nil {
+		panic("stemmer: Register stemmer is nil")
+	}
+	if _, dup := registry[name]; dup {
+		panic("stemmer: Register called twice for stemmer " + name)
+	}
+	registry[name] = s
+}
+
+//
+// Lookup returns the stemmer registered under name.
+//
+func Lookup(name string) (Stemmer, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	s, ok := registry[name]
+	return s, ok
+}
+
+//
+// Names returns the sorted names of the registered stemmers.
+//
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//
+// Porter is the Stemmer for the original Porter algorithm implemented by Stem.
+//
+type Porter struct {
+	// FoldAccents spells words in ASCII with FoldAccents before stemming them,
+	// so that "naïve" and "naive" have the same stem.
+	FoldAccents bool
+}
+
+func (p Porter) Stem(word []byte) []byte {
+	if p.FoldAccents {
+		return Stem(FoldAccents(word))
+	}
+	return Stem(word)
+}
+
+func (p Porter) StemString(word string) string {
+	if p.FoldAccents {
+		return StemString(string(appendFolded(nil, word)))
+	}
+	return StemString(word)
+}
+
+func (Porter) Name() string { return "porter" }
+
+//
+// StemTrace is StemTrace for p.
+//
+func (p Porter) StemTrace(word []byte) (stem []byte, steps []StepResult) {
+	if p.FoldAccents {
+		return StemTrace(FoldAccents(word))
+	}
+	return StemTrace(word)
+}
+
+//
+// Porter2 is the Stemmer for the Snowball English algorithm implemented by
+// StemPorter2.
+//
+type Porter2 struct {
+	// FoldAccents is as for Porter.
+	FoldAccents bool
+}
+
+func (p Porter2) Stem(word []byte) []byte {
+	if p.FoldAccents {
+		return StemPorter2(FoldAccents(word))
+	}
+	return StemPorter2(word)
+}
+
+func (p Porter2) StemString(word string) string {
+	if p.FoldAccents {
+		return string(StemPorter2(appendFolded(nil, word)))
+	}
+	return stringResult(word, StemPorter2([]byte(word)))
+}
+
+func (Porter2) Name() string { return "porter2" }
+
+//
+// Lovins is the Stemmer for the Lovins algorithm implemented by StemLovins.
+//
+type Lovins struct{}
+
+func (Lovins) Stem(word []byte) []byte { return StemLovins(word) }
+
+func (Lovins) StemString(word string) string {
+	return stringResult(word, StemLovins([]byte(word)))
+}
+
+func (Lovins) Name() string { return "lovins" }
+
+//
+// stringResult converts stem to a string, returning word itself when they are
+// equal.
+//
+func stringResult(word string, stem []byte) string {
+	if string(stem) == word {
+		return word
+	}
+	return string(stem)
+}
@@ -0,0 +1,83 @@
+package stemmer
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+)
+
+type upper struct{}
+
+func (upper) Stem(word []byte) []byte       { return bytes.ToUpper(word) }
+func (upper) StemString(word string) string { return string(bytes.ToUpper([]byte(word))) }
+func (upper) Name() string                  { return "upper" }
+
+func TestLookup(t *testing.T) {
+	fixtures := []string{
+		"porter",
+		"porter2",
+		"lovins",
+		"lancaster",
+		"krovetz",
+	}
+
+	stemmed := []string{
+		"gener",
+		"generous",
+		"gener",
+		"gen",
+		"generously",
+	}
+
+	for k, value := range fixtures {
+		s, ok := Lookup(value)
+		if !ok {
+			t.Fatalf("Lookup() did not find stemmer '%s'", value)
+		}
+		if name := s.Name(); name != value {
+			t.Errorf("Name() return value not what was expected, return: '%s' expected: '%s'", name, value)
+		}
+		if result := s.StemString("generously"); result != stemmed[k] {
+			t.Errorf("%s StemString() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, "generously", result, stemmed[k])
+		}
+		if result := s.Stem([]byte("generously")); !bytes.Equal(result, []byte(stemmed[k])) {
+			t.Errorf("%s Stem() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, "generously", result, stemmed[k])
+		}
+	}
+
+	if _, ok := Lookup("klingon"); ok {
+		t.Errorf("Lookup() found a stemmer that was never registered: '%s'", "klingon")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("upper", upper{})
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "upper")
+		registryMu.Unlock()
+	}()
+
+	if s, ok := Lookup("upper"); !ok || s.StemString("abc") != "ABC" {
+		t.Errorf("Lookup() did not return the registered stemmer '%s'", "upper")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Register() did not panic on a duplicate name '%s'", "upper")
+		}
+	}()
+	Register("upper", upper{})
+}
+
+func TestNames(t *testing.T) {
+	names := Names()
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("Names() return value is not sorted: '%v'", names)
+	}
+	for _, name := range []string{"porter", "porter2", "da", "de", "es", "fi", "fr", "it", "nl", "no", "pt", "ru", "sv"} {
+		if i := sort.SearchStrings(names, name); i == len(names) || names[i] != name {
+			t.Errorf("Names() return value does not contain '%s': '%v'", name, names)
+		}
+	}
+}
@@ -0,0 +1,380 @@
+package stemmer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+//
+// A rule file describes a RuleStemmer in JSON:
+//
+//    {
+//      "name": "porter",
+//      "steps": [
+//        {"name": "1b", "rules": [
+//          {"suffix": "eed", "replacement": "ee", "condition": "m>0"},
+//          {"suffix": "ed", "condition": "*v*", "then": "1b2"}
+//        ]}
+//      ],
+//      "substeps": [
+//        {"name": "1b2", "rules": [
+//          {"suffix": "at", "replacement": "ate"}
+//        ]}
+//      ]
+//    }
+//
+// The steps run in order; substeps only run through the "then" of a rule,
+// which names a step or a substep; a "then" must not lead back to the step
+// of its rule. Conditions use the notation of the paper, see condition.go.
+// rules/porter.json is the file for the rules of Stem. Only JSON is read, not
+// YAML, which would need a third-party parser.
+//
+
+//
+// RuleSet is the content of a rule file.
+//
+type RuleSet struct {
+	Name  string
+	Steps []Step
+}
+
+//
+// RuleFileError reports a problem in a rule file along with the line where it
+// was found.
+//
+type RuleFileError struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e *RuleFileError) Error() string {
+	return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+}
+
+//
+// LoadRules reads the rule file at path and compiles it into a RuleStemmer.
+//
+func LoadRules(path string) (*RuleStemmer, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := ParseRules(path, data)
+	if err != nil {
+		return nil, err
+	}
+	return NewRuleStemmer(rs.Name, rs.Steps)
+}
+
+//
+// ReadRules is LoadRules for a rule file read from r. file names it in errors.
+//
+func ReadRules(file string, r io.Reader) (*RuleStemmer, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := ParseRules(file, data)
+	if err != nil {
+		return nil, err
+	}
+	return NewRuleStemmer(rs.Name, rs.Steps)
+}
+
+//
+// ParseRules parses and validates the rule file data. file names it in
+// errors, which are all *RuleFileError.
+//
+func ParseRules(file string, data []byte) (*RuleSet, error) {
+	p := &ruleFileParser{file: file, data: data, dec: json.NewDecoder(bytes.NewReader(data))}
+	p.dec.DisallowUnknownFields()
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.link()
+}
+
+type ruleFile struct {
+	name  string
+	line  int
+	rules []ruleFileRule
+}
+
+type ruleFileRule struct {
+	line int
+	rule struct {
+		Suffix      *string `json:"suffix"`
+		Replacement string  `json:"replacement"`
+		Condition   string  `json:"condition"`
+		Then        string  `json:"then"`
+	}
+}
+
+type ruleFileParser struct {
+	file     string
+	data     []byte
+	dec      *json.Decoder
+	name     string
+	steps    []*ruleFile
+	substeps []*ruleFile
+}
+
+//
+// errorAt returns a *RuleFileError for the line of the byte at off, skipping
+// the blanks and separators between JSON values.
+//
+func (p *ruleFileParser) errorAt(off int64, format string, args ...interface{}) error {
+	return &RuleFileError{File: p.file, Line: p.line(off), Err: fmt.Errorf(format, args...)}
+}
+
+func (p *ruleFileParser) line(off int64) int {
+	for off < int64(len(p.data)) && bytes.IndexByte([]byte(" \t\r\n,:"), p.data[off]) >= 0 {
+		off++
+	}
+	if off > int64(len(p.data)) {
+		off = int64(len(p.data))
+	}
+	return bytes.Count(p.data[:off], []byte("\n")) + 1
+}
+
+//
+// fail turns an error of the decoder into a *RuleFileError.
+//
+func (p *ruleFileParser) fail(err error) error {
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		return p.errorAt(e.Offset-1, "%v", err)
+	case *json.UnmarshalTypeError:
+		return p.errorAt(e.Offset-1, "%v", err)
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return p.errorAt(p.dec.InputOffset(), "%v", err)
+}
+
+func (p *ruleFileParser) delim(want json.Delim) error {
+	off := p.dec.InputOffset()
+	tok, err := p.dec.Token()
+	if err != nil {
+		return p.fail(err)
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return p.errorAt(off, "expected %v, found %v", want, tok)
+	}
+	return nil
+}
+
+func (p *ruleFileParser) key() (string, int64, error) {
+	off := p.dec.InputOffset()
+	tok, err := p.dec.Token()
+	if err != nil {
+		return "", off, p.fail(err)
+	}
+	return tok.(string), off, nil
+}
+
+func (p *ruleFileParser) parse() error {
+	if err := p.delim('{'); err != nil {
+		return err
+	}
+	for p.dec.More() {
+		key, off, err := p.key()
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "name":
+			if err := p.dec.Decode(&p.name); err != nil {
+				return p.fail(err)
+			}
+		case "steps":
+			if p.steps, err = p.parseSteps(); err != nil {
+				return err
+			}
+		case "substeps":
+			if p.substeps, err = p.parseSteps(); err != nil {
+				return err
+			}
+		default:
+			return p.errorAt(off, "unknown field %q", key)
+		}
+	}
+	if err := p.delim('}'); err != nil {
+		return err
+	}
+	if off := p.dec.InputOffset(); p.dec.More() {
+		return p.errorAt(off, "data after the end of the rules")
+	}
+	return nil
+}
+
+func (p *ruleFileParser) parseSteps() ([]*ruleFile, error) {
+	if err := p.delim('['); err != nil {
+		return nil, err
+	}
+	var steps []*ruleFile
+	for p.dec.More() {
+		step := &ruleFile{line: p.line(p.dec.InputOffset())}
+		if err := p.delim('{'); err != nil {
+			return nil, err
+		}
+		for p.dec.More() {
+			key, off, err := p.key()
+			if err != nil {
+				return nil, err
+			}
+			switch key {
+			case "name":
+				if err := p.dec.Decode(&step.name); err != nil {
+					return nil, p.fail(err)
+				}
+			case "rules":
+				if step.rules, err = p.parseRules(); err != nil {
+					return nil, err
+				}
+			default:
+				return nil, p.errorAt(off, "unknown field %q", key)
+			}
+		}
+		if err := p.delim('}'); err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	if err := p.delim(']'); err != nil {
+		return nil, err
+	}
+	return steps, nil
+}
+
+func (p *ruleFileParser) parseRules() ([]ruleFileRule, error) {
+	if err := p.delim('['); err != nil {
+		return nil, err
+	}
+	var rules []ruleFileRule
+	for p.dec.More() {
+		var r ruleFileRule
+		r.line = p.line(p.dec.InputOffset())
+		if err := p.dec.Decode(&r.rule); err != nil {
+			if _, ok := err.(*json.SyntaxError); ok {
+				return nil, p.fail(err)
+			}
+			return nil, &RuleFileError{File: p.file, Line: r.line, Err: err}
+		}
+		rules = append(rules, r)
+	}
+	if err := p.delim(']'); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+//
+// link validates the parsed steps and turns them into a RuleSet, resolving
+// the "then" of the rules.
+//
+func (p *ruleFileParser) link() (*RuleSet, error) {
+	errorf := func(line int, format string, args ...interface{}) error {
+		return &RuleFileError{File: p.file, Line: line, Err: fmt.Errorf(format, args...)}
+	}
+	if p.name == "" {
+		return nil, errorf(1, "missing name")
+	}
+	if len(p.steps) == 0 {
+		return nil, errorf(1, "no steps")
+	}
+
+	rs := &RuleSet{Name: p.name, Steps: make([]Step, len(p.steps))}
+	substeps := make([]Step, len(p.substeps))
+	byName := make(map[string]*Step)
+	files := append(append([]*ruleFile(nil), p.steps...), p.substeps...)
+	for i, f := range files {
+		if f.name == "" {
+			return nil, errorf(f.line, "step without a name")
+		}
+		if _, dup := byName[f.name]; dup {
+			return nil, errorf(f.line, "duplicate step %q", f.name)
+		}
+		var step *Step
+		if i < len(rs.Steps) {
+			step = &rs.Steps[i]
+		} else {
+			step = &substeps[i-len(rs.Steps)]
+		}
+		step.Name = f.name
+		byName[f.name] = step
+	}
+
+	// thens holds the steps each step leads to, with the line of the rule.
+	type then struct {
+		step *Step
+		line int
+	}
+	thens := make(map[*Step][]then)
+	for _, f := range files {
+		step := byName[f.name]
+		seen := make(map[string]bool)
+		for _, r := range f.rules {
+			if r.rule.Suffix == nil {
+				return nil, errorf(r.line, "rule without a suffix")
+			}
+			suffix := *r.rule.Suffix
+			if seen[strings.ToLower(suffix)] {
+				return nil, errorf(r.line, "duplicate suffix %q in step %s", suffix, f.name)
+			}
+			seen[strings.ToLower(suffix)] = true
+			if _, err := parseCondition(r.rule.Condition); err != nil {
+				return nil, &RuleFileError{File: p.file, Line: r.line, Err: err}
+			}
+			rule := Rule{
+				Suffix:      suffix,
+				Replacement: r.rule.Replacement,
+				Condition:   r.rule.Condition,
+			}
+			if r.rule.Then != "" {
+				if rule.Then = byName[r.rule.Then]; rule.Then == nil {
+					return nil, errorf(r.line, "unknown step %q", r.rule.Then)
+				}
+				thens[step] = append(thens[step], then{rule.Then, r.line})
+			}
+			step.Rules = append(step.Rules, rule)
+		}
+	}
+
+	// A then that leads back to the step of its rule would loop for ever.
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[*Step]int)
+	var visit func(step *Step) error
+	visit = func(step *Step) error {
+		state[step] = visiting
+		for _, t := range thens[step] {
+			switch state[t.step] {
+			case visiting:
+				return errorf(t.line, "step %s leads back to step %s", step.Name, t.step.Name)
+			case 0:
+				if err := visit(t.step); err != nil {
+					return err
+				}
+			}
+		}
+		state[step] = done
+		return nil
+	}
+	for _, f := range files {
+		if step := byName[f.name]; state[step] == 0 {
+			if err := visit(step); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return rs, nil
+}
@@ -0,0 +1,116 @@
+package stemmer
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadRulesVocal(t *testing.T) {
+	s, err := LoadRules("rules/porter.json")
+	if err != nil {
+		t.Fatalf("LoadRules() returned an error: '%v'", err)
+	}
+
+	v, err := os.Open("voc.txt")
+	if err != nil {
+		panic(err)
+	}
+	defer v.Close()
+	vocScanner := bufio.NewScanner(v)
+
+	o, err := os.Open("output.txt")
+	if err != nil {
+		panic(err)
+	}
+	defer o.Close()
+	outputScanner := bufio.NewScanner(o)
+
+	for vocScanner.Scan() && outputScanner.Scan() {
+		word := vocScanner.Bytes()
+		expected := outputScanner.Bytes()
+		if result := s.Stem(word); !bytes.Equal(result, expected) {
+			t.Errorf("RuleStemmer.Stem() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", word, result, expected)
+		}
+	}
+}
+
+func TestReadRules(t *testing.T) {
+	s, err := ReadRules("plural.json", strings.NewReader(`{
+  "name": "plural",
+  "steps": [
+    {"name": "1", "rules": [
+      {"suffix": "ies", "replacement": "y", "condition": "m>0", "then": "2"},
+      {"suffix": "s", "replacement": ""}
+    ]}
+  ],
+  "substeps": [
+    {"name": "2", "rules": [
+      {"suffix": "ly", "replacement": "l"}
+    ]}
+  ]
+}`))
+	if err != nil {
+		t.Fatalf("ReadRules() returned an error: '%v'", err)
+	}
+	if s.Name() != "plural" {
+		t.Errorf("RuleStemmer.Name() return value not what was expected, return: '%s' expected: 'plural'", s.Name())
+	}
+
+	fixtures := []string{"cats", "ponies", "lilies"}
+	stemmed := []string{"cat", "pony", "lil"}
+
+	for k, value := range fixtures {
+		if result := s.StemString(value); result != stemmed[k] {
+			t.Errorf("RuleStemmer.StemString() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, stemmed[k])
+		}
+	}
+}
+
+func TestParseRulesError(t *testing.T) {
+	fixtures := []string{
+		"{\n  \"name\": \"x\",\n  \"steps\": [\n    {\"name\": \"1\", \"rules\": [\n      {\"suffix\": \"s\", \"condition\": \"m>\"}\n    ]}\n  ]\n}",
+		"{\n  \"name\": \"x\",\n  \"steps\": [\n    {\"name\": \"1\", \"rules\": [\n      {\"suffix\": \"s\"},\n      {\"suffix\": \"s\"}\n    ]}\n  ]\n}",
+		"{\n  \"name\": \"x\",\n  \"steps\": [\n    {\"name\": \"1\", \"rules\": [\n      {\"suffix\": \"ss\"},\n      {\"suffix\": \"SS\"}\n    ]}\n  ]\n}",
+		"{\n  \"name\": \"x\",\n  \"steps\": [\n    {\"name\": \"1\", \"rules\": [\n      {\"suffix\": \"s\", \"then\": \"2\"}\n    ]}\n  ]\n}",
+		"{\n  \"name\": \"x\",\n  \"steps\": [\n    {\"name\": \"1\", \"rules\": [\n      {\"suffix\": \"s\", \"replace\": \"\"}\n    ]}\n  ]\n}",
+		"{\n  \"name\": \"x\",\n  \"steps\": [\n    {\"name\": \"1\", \"rules\": [\n      {\"replacement\": \"\"}\n    ]}\n  ]\n}",
+		"{\n  \"name\": \"x\",\n  \"steps\": [\n    {\"name\": \"1\", \"rules\": []},\n    {\"name\": \"1\", \"rules\": []}\n  ]\n}",
+		"{\n  \"name\": \"x\",\n  \"steps\": [\n    {\"name\": \"1\" \"rules\": []}\n  ]\n}",
+		"{\n  \"name\": \"x\",\n  \"stages\": []\n}",
+		"{\n  \"name\": \"x\",\n  \"steps\": [\n    {\"name\": \"1\", \"rules\": [\n      {\"suffix\": 1}\n    ]}\n  ]\n}",
+		"{\n  \"name\": \"x\"\n}",
+		`{"name":"x","steps":[{"name":"1","rules":[{"suffix":"ss","replacement":"ss","then":"1"}]}]}`,
+		"{\n  \"name\": \"x\",\n  \"steps\": [\n    {\"name\": \"1\", \"rules\": [\n      {\"suffix\": \"s\", \"then\": \"2\"}\n    ]}\n  ],\n  \"substeps\": [\n    {\"name\": \"2\", \"rules\": [\n      {\"suffix\": \"es\", \"then\": \"3\"}\n    ]},\n    {\"name\": \"3\", \"rules\": [\n      {\"suffix\": \"e\", \"then\": \"2\"}\n    ]}\n  ]\n}",
+	}
+
+	lines := []int{
+		5,
+		6,
+		6,
+		5,
+		5,
+		5,
+		5,
+		4,
+		3,
+		5,
+		1,
+		1,
+		13,
+	}
+
+	for k, value := range fixtures {
+		_, err := ParseRules("broken.json", []byte(value))
+		e, ok := err.(*RuleFileError)
+		if !ok {
+			t.Errorf("ParseRules() did not return a *RuleFileError, pass: '%s' return: '%v'", value, err)
+			continue
+		}
+		if e.Line != lines[k] {
+			t.Errorf("ParseRules() line not what was expected, pass: '%s' return: '%d' expected: '%d' error: '%v'", value, e.Line, lines[k], err)
+		}
+	}
+}
@@ -0,0 +1,535 @@
+package stemmer
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+//
+// Rule is a rule of a step: if the word ends with Suffix and the stem left
+// once Suffix is removed satisfies Condition, Suffix is replaced by
+// Replacement. Condition uses the notation of the paper, see condition.go. If
+// the rule applies and Then is not nil, the step Then is run on the result;
+// Then must not lead back to the step of the rule.
+//
+type Rule struct {
+	Suffix      string
+	Replacement string
+	Condition   string
+	Then        *Step
+}
+
+//
+// Step is a table of rules. Only the rule with the longest matching Suffix is
+// considered: if its condition does not hold, the step leaves the word alone.
+// A rule with an empty Suffix matches any word no other rule matches.
+//
+type Step struct {
+	Name  string
+	Rules []Rule
+}
+
+//
+// The tables below are the rules Stem runs. They can be read, or copied and
+// changed to build a RuleStemmer with NewRuleStemmer; changing them in place
+// has no effect on Stem.
+//
+
+//
+// Step 1a
+//
+//    SSES -> SS                         caresses  ->  caress
+//    IES  -> I                          ponies    ->  poni
+//                                       ties      ->  ti
+//    SS   -> SS                         caress    ->  caress
+//    S    ->                            cats      ->  cat
+//
+var PorterStep1a = Step{
+	Name: "1a",
+	Rules: []Rule{
+		{Suffix: "sses", Replacement: "ss"},
+		{Suffix: "ies", Replacement: "i"},
+		{Suffix: "ss", Replacement: "ss"},
+		{Suffix: "s", Replacement: ""},
+	},
+}
+
+//
+// Step 1b
+//
+//    (m>0) EED -> EE                    feed      ->  feed
+//                                       agreed    ->  agree
+//    (*v*) ED  ->                       plastered ->  plaster
+//                                       bled      ->  bled
+//    (*v*) ING ->                       motoring  ->  motor
+//                                       sing      ->  sing
+//
+var PorterStep1b = Step{
+	Name: "1b",
+	Rules: []Rule{
+		{Suffix: "eed", Replacement: "ee", Condition: "m>0"},
+		{Suffix: "ed", Replacement: "", Condition: "*v*", Then: &PorterStep1b2},
+		{Suffix: "ing", Replacement: "", Condition: "*v*", Then: &PorterStep1b2},
+	},
+}
+
+//
+// Step 1b, when the second or third rule of Step 1b applied
+//
+//  AT -> ATE                       conflat(ed)  ->  conflate
+//  BL -> BLE                       troubl(ed)   ->  trouble
+//  IZ -> IZE                       siz(ed)      ->  size
+//  (*d and not (*L or *S or *Z))
+//     -> single letter
+//                                  hopp(ing)    ->  hop
+//                                  tann(ed)     ->  tan
+//                                  fall(ing)    ->  fall
+//                                  hiss(ing)    ->  hiss
+//                                  fizz(ed)     ->  fizz
+//  (m=1 and *o) -> E               fail(ing)    ->  fail
+//                                  fil(ing)     ->  file
+//
+// The *d rule is spelled out as one rule per double consonant, with LL, SS
+// and ZZ mapped to themselves so that they do not fall through to the last
+// rule.
+//
+var PorterStep1b2 = Step{
+	Name: "1b2",
+	Rules: []Rule{
+		{Suffix: "at", Replacement: "ate"},
+		{Suffix: "bl", Replacement: "ble"},
+		{Suffix: "iz", Replacement: "ize"},
+		{Suffix: "bb", Replacement: "b"},
+		{Suffix: "cc", Replacement: "c"},
+		{Suffix: "dd", Replacement: "d"},
+		{Suffix: "ff", Replacement: "f"},
+		{Suffix: "gg", Replacement: "g"},
+		{Suffix: "hh", Replacement: "h"},
+		{Suffix: "jj", Replacement: "j"},
+		{Suffix: "kk", Replacement: "k"},
+		{Suffix: "mm", Replacement: "m"},
+		{Suffix: "nn", Replacement: "n"},
+		{Suffix: "pp", Replacement: "p"},
+		{Suffix: "qq", Replacement: "q"},
+		{Suffix: "rr", Replacement: "r"},
+		{Suffix: "tt", Replacement: "t"},
+		{Suffix: "vv", Replacement: "v"},
+		{Suffix: "ww", Replacement: "w"},
+		{Suffix: "xx", Replacement: "x"},
+		{Suffix: "ll", Replacement: "ll"},
+		{Suffix: "ss", Replacement: "ss"},
+		{Suffix: "zz", Replacement: "zz"},
+		{Suffix: "", Replacement: "e", Condition: "m=1 and *o"},
+	},
+}
+
+//
+// Step 1c
+//
+//    (*v*) Y -> I                    happy        ->  happi
+//                                    sky          ->  sky
+//
+var PorterStep1c = Step{
+	Name: "1c",
+	Rules: []Rule{
+		{Suffix: "y", Replacement: "i", Condition: "*v*"},
+	},
+}
+
+//
+// Step 2
+//
+//    (m>0) ATIONAL ->  ATE           relational     ->  relate
+//    (m>0) TIONAL  ->  TION          conditional    ->  condition
+//                                    rational       ->  rational
+//    (m>0) ENCI    ->  ENCE          valenci        ->  valence
+//    (m>0) ANCI    ->  ANCE          hesitanci      ->  hesitance
+//    (m>0) IZER    ->  IZE           digitizer      ->  digitize
+//    (m>0) ABLI    ->  ABLE          conformabli    ->  conformable
+//    (m>0) BLI     ->  BLE           possibli       ->  possible
+//    (m>0) ALLI    ->  AL            radicalli      ->  radical
+//    (m>0) ENTLI   ->  ENT           differentli    ->  different
+//    (m>0) ELI     ->  E             vileli        - >  vile
+//    (m>0) OUSLI   ->  OUS           analogousli    ->  analogous
+//    (m>0) IZATION ->  IZE           vietnamization ->  vietnamize
+//    (m>0) ATION   ->  ATE           predication    ->  predicate
+//    (m>0) ATOR    ->  ATE           operator       ->  operate
+//    (m>0) ALISM   ->  AL            feudalism      ->  feudal
+//    (m>0) IVENESS ->  IVE           decisiveness   ->  decisive
+//    (m>0) FULNESS ->  FUL           hopefulness    ->  hopeful
+//    (m>0) OUSNESS ->  OUS           callousness    ->  callous
+//    (m>0) ALITI   ->  AL            formaliti      ->  formal
+//    (m>0) IVITI   ->  IVE           sensitiviti    ->  sensitive
+//    (m>0) BILITI  ->  BLE           sensibiliti    ->  sensible
+//    (m>0) LOGI    ->  LOG           apologi        ->  apolog
+//
+var PorterStep2 = Step{
+	Name: "2",
+	Rules: []Rule{
+		{Suffix: "ational", Replacement: "ate", Condition: "m>0"},
+		{Suffix: "tional", Replacement: "tion", Condition: "m>0"},
+		{Suffix: "enci", Replacement: "ence", Condition: "m>0"},
+		{Suffix: "anci", Replacement: "ance", Condition: "m>0"},
+		{Suffix: "izer", Replacement: "ize", Condition: "m>0"},
+		{Suffix: "abli", Replacement: "able", Condition: "m>0"},
+		{Suffix: "bli", Replacement: "ble", Condition: "m>0"},
+		{Suffix: "alli", Replacement: "al", Condition: "m>0"},
+		{Suffix: "entli", Replacement: "ent", Condition: "m>0"},
+		{Suffix: "eli", Replacement: "e", Condition: "m>0"},
+		{Suffix: "ousli", Replacement: "ous", Condition: "m>0"},
+		{Suffix: "ization", Replacement: "ize", Condition: "m>0"},
+		{Suffix: "ation", Replacement: "ate", Condition: "m>0"},
+		{Suffix: "ator", Replacement: "ate", Condition: "m>0"},
+		{Suffix: "alism", Replacement: "al", Condition: "m>0"},
+		{Suffix: "iveness", Replacement: "ive", Condition: "m>0"},
+		{Suffix: "fulness", Replacement: "ful", Condition: "m>0"},
+		{Suffix: "ousness", Replacement: "ous", Condition: "m>0"},
+		{Suffix: "aliti", Replacement: "al", Condition: "m>0"},
+		{Suffix: "iviti", Replacement: "ive", Condition: "m>0"},
+		{Suffix: "biliti", Replacement: "ble", Condition: "m>0"},
+		{Suffix: "logi", Replacement: "log", Condition: "m>0"},
+	},
+}
+
+//
+// Step 3
+//
+//    (m>0) ICATE ->  IC              triplicate     ->  triplic
+//    (m>0) ATIVE ->                  formative      ->  form
+//    (m>0) ALIZE ->  AL              formalize      ->  formal
+//    (m>0) ICITI ->  IC              electriciti    ->  electric
+//    (m>0) ICAL  ->  IC              electrical     ->  electric
+//    (m>0) FUL   ->                  hopeful        ->  hope
+//    (m>0) NESS  ->                  goodness       ->  good
+//
+var PorterStep3 = Step{
+	Name: "3",
+	Rules: []Rule{
+		{Suffix: "icate", Replacement: "ic", Condition: "m>0"},
+		{Suffix: "ative", Replacement: "", Condition: "m>0"},
+		{Suffix: "alize", Replacement: "al", Condition: "m>0"},
+		{Suffix: "iciti", Replacement: "ic", Condition: "m>0"},
+		{Suffix: "ical", Replacement: "ic", Condition: "m>0"},
+		{Suffix: "ful", Replacement: "", Condition: "m>0"},
+		{Suffix: "ness", Replacement: "", Condition: "m>0"},
+	},
+}
+
+//
+// Step 4
+//
+//    (m>1) AL    ->                  revival        ->  reviv
+//    (m>1) ANCE  ->                  allowance      ->  allow
+//    (m>1) ENCE  ->                  inference      ->  infer
+//    (m>1) ER    ->                  airliner       ->  airlin
+//    (m>1) IC    ->                  gyroscopic     ->  gyroscop
+//    (m>1) ABLE  ->                  adjustable     ->  adjust
+//    (m>1) IBLE  ->                  defensible     ->  defens
+//    (m>1) ANT   ->                  irritant       ->  irrit
+//    (m>1) EMENT ->                  replacement    ->  replac
+//    (m>1) MENT  ->                  adjustment     ->  adjust
+//    (m>1) ENT   ->                  dependent      ->  depend
+//    (m>1 and (*S or *T)) ION ->     adoption       ->  adopt
+//    (m>1) OU    ->                  homologou      ->  homolog
+//    (m>1) ISM   ->                  communism      ->  commun
+//    (m>1) ATE   ->                  activate       ->  activ
+//    (m>1) ITI   ->                  angulariti     ->  angular
+//    (m>1) OUS   ->                  homologous     ->  homolog
+//    (m>1) IVE   ->                  effective      ->  effect
+//    (m>1) IZE   ->                  bowdlerize     ->  bowdler
+//
+var PorterStep4 = Step{
+	Name: "4",
+	Rules: []Rule{
+		{Suffix: "al", Replacement: "", Condition: "m>1"},
+		{Suffix: "ance", Replacement: "", Condition: "m>1"},
+		{Suffix: "ence", Replacement: "", Condition: "m>1"},
+		{Suffix: "er", Replacement: "", Condition: "m>1"},
+		{Suffix: "ic", Replacement: "", Condition: "m>1"},
+		{Suffix: "able", Replacement: "", Condition: "m>1"},
+		{Suffix: "ible", Replacement: "", Condition: "m>1"},
+		{Suffix: "ant", Replacement: "", Condition: "m>1"},
+		{Suffix: "ement", Replacement: "", Condition: "m>1"},
+		{Suffix: "ment", Replacement: "", Condition: "m>1"},
+		{Suffix: "ent", Replacement: "", Condition: "m>1"},
+		{Suffix: "ion", Replacement: "", Condition: "m>1 and (*S or *T)"},
+		{Suffix: "ou", Replacement: "", Condition: "m>1"},
+		{Suffix: "ism", Replacement: "", Condition: "m>1"},
+		{Suffix: "ate", Replacement: "", Condition: "m>1"},
+		{Suffix: "iti", Replacement: "", Condition: "m>1"},
+		{Suffix: "ous", Replacement: "", Condition: "m>1"},
+		{Suffix: "ive", Replacement: "", Condition: "m>1"},
+		{Suffix: "ize", Replacement: "", Condition: "m>1"},
+	},
+}
+
+//
+// Step 5a
+//
+//    (m>1) E     ->                  probate        ->  probat
+//                                    rate           ->  rate
+//    (m=1 and not *o) E ->           cease          ->  ceas
+//
+var PorterStep5a = Step{
+	Name: "5a",
+	Rules: []Rule{
+		{Suffix: "e", Replacement: "", Condition: "m>1 or (m=1 and not *o)"},
+	},
+}
+
+//
+// Step 5b
+//
+//    (m > 1 and *d and *L) -> single letter
+//                                    controll       ->  control
+//                                    roll           ->  roll
+//
+// written as the removal of the last L of a stem that ends with L.
+//
+var PorterStep5b = Step{
+	Name: "5b",
+	Rules: []Rule{
+		{Suffix: "l", Replacement: "", Condition: "m>1 and *L"},
+	},
+}
+
+//
+// PorterSteps are the steps of Stem, in the order they run. Step 1b2 is run
+// by the rules of Step 1b.
+//
+var PorterSteps = []Step{
+	PorterStep1a,
+	PorterStep1b,
+	PorterStep1c,
+	PorterStep2,
+	PorterStep3,
+	PorterStep4,
+	PorterStep5a,
+	PorterStep5b,
+}
+
+//
+// porterRules runs the tables above for StemTrace. Stem runs porterRun, the
+// same rules compiled to Go from rules/porter.json.
+//
+var porterRules = MustNewRuleStemmer("porter", PorterSteps)
+
+//go:generate go run ./cmd/stemgen -o porter_gen.go rules/porter.json
+
+//
+// RuleStemmer is a Stemmer that runs a list of rule tables. Like Stem, it
+// lower cases and trims the word first and leaves words shorter than three
+// letters alone.
+//
+type RuleStemmer struct {
+	name  string
+	steps []*compiledStep
+}
+
+type compiledStep struct {
+	name string
+	// bySuffix holds the rules by the last byte of their suffix, longest
+	// suffix first; empty is the rule with an empty suffix, if any.
+	bySuffix [256][]*compiledRule
+	empty    *compiledRule
+}
+
+type compiledRule struct {
+	suffix      string
+	replacement string
+	condition   string
+	cond        *condition
+	then        *compiledStep
+}
+
+//
+// NewRuleStemmer compiles steps into a RuleStemmer called name.
+//
+func NewRuleStemmer(name string, steps []Step) (*RuleStemmer, error) {
+	s := &RuleStemmer{name: name}
+	compiled := make(map[*Step]*compiledStep)
+	for i := range steps {
+		step, err := compileStep(&steps[i], compiled, make(map[*Step]bool))
+		if err != nil {
+			return nil, err
+		}
+		s.steps = append(s.steps, step)
+	}
+	return s, nil
+}
+
+//
+// MustNewRuleStemmer is like NewRuleStemmer but panics if the steps cannot be
+// compiled.
+//
+func MustNewRuleStemmer(name string, steps []Step) *RuleStemmer {
+	s, err := NewRuleStemmer(name, steps)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+//
+// compileStep compiles step, reusing the steps in compiled so that a step
+// shared by several rules through Then is compiled once. path holds the steps
+// whose Then led to step: reaching one of them again would loop for ever.
+//
+func compileStep(step *Step, compiled map[*Step]*compiledStep, path map[*Step]bool) (*compiledStep, error) {
+	if path[step] {
+		return nil, fmt.Errorf("stemmer: step %s: Then leads back to the step", step.Name)
+	}
+	if c, ok := compiled[step]; ok {
+		return c, nil
+	}
+	c := &compiledStep{name: step.Name}
+	compiled[step] = c
+	path[step] = true
+	defer delete(path, step)
+	seen := make(map[string]bool)
+	for _, rule := range step.Rules {
+		// Suffixes are compared in lower case, like the words.
+		suffix := strings.ToLower(rule.Suffix)
+		if seen[suffix] {
+			return nil, fmt.Errorf("stemmer: step %s: duplicate suffix %q", step.Name, rule.Suffix)
+		}
+		seen[suffix] = true
+		cond, err := parseCondition(rule.Condition)
+		if err != nil {
+			return nil, fmt.Errorf("stemmer: step %s: suffix %q: %v", step.Name, rule.Suffix, err)
+		}
+		r := &compiledRule{
+			suffix:      suffix,
+			replacement: strings.ToLower(rule.Replacement),
+			condition:   rule.Condition,
+			cond:        cond,
+		}
+		if rule.Then != nil {
+			if r.then, err = compileStep(rule.Then, compiled, path); err != nil {
+				return nil, err
+			}
+		}
+		if r.suffix == "" {
+			c.empty = r
+			continue
+		}
+		last := r.suffix[len(r.suffix)-1]
+		rules := append(c.bySuffix[last], r)
+		for i := len(rules) - 1; i > 0 && len(rules[i].suffix) > len(rules[i-1].suffix); i-- {
+			rules[i], rules[i-1] = rules[i-1], rules[i]
+		}
+		c.bySuffix[last] = rules
+	}
+	return c, nil
+}
+
+//
+// apply runs the step on word, rewriting it in place.
+//
+func (s *compiledStep) apply(word []byte, t *trace) []byte {
+	r := s.empty
+	if len(word) > 0 {
+		for _, rule := range s.bySuffix[word[len(word)-1]] {
+			if len(rule.suffix) <= len(word) && string(word[len(word)-len(rule.suffix):]) == rule.suffix {
+				r = rule
+				break
+			}
+		}
+	}
+	if r == nil {
+		return word
+	}
+	t.match(word, len(r.suffix), r.condition)
+	stem := word[:len(word)-len(r.suffix)]
+	if !r.cond.eval(stem) {
+		return word
+	}
+	word = append(stem, r.replacement...)
+	if r.then != nil {
+		t.begin(r.then.name, word)
+		word = r.then.apply(word, t)
+	}
+	return word
+}
+
+//
+// run runs all the steps over word, which must already be lower case,
+// rewriting it in place. The rules that match are recorded in t unless it is
+// nil.
+//
+func (s *RuleStemmer) run(word []byte, t *trace) []byte {
+	if len(word) < 3 {
+		return word
+	}
+	for _, step := range s.steps {
+		t.begin(step.name, word)
+		word = step.apply(word, t)
+	}
+	return word
+}
+
+func (s *RuleStemmer) Name() string { return s.name }
+
+//
+// Stem returns the stem of word in a newly allocated slice.
+//
+func (s *RuleStemmer) Stem(word []byte) []byte {
+	return s.AppendStem(make([]byte, 0, len(word)), word)
+}
+
+//
+// AppendStem appends the stem of word to dst and returns the extended buffer.
+// word is never modified, and dst and word must not overlap.
+//
+func (s *RuleStemmer) AppendStem(dst, word []byte) []byte {
+	n := len(dst)
+	dst = appendLower(dst, bytes.TrimSpace(word))
+	return append(dst[:n], s.run(dst[n:], nil)...)
+}
+
+//
+// StemString returns the stem of word, or word itself if nothing changed.
+//
+func (s *RuleStemmer) StemString(word string) string {
+	var buf [64]byte
+	stem, _ := s.stemString(buf[:0], word)
+	return stem
+}
+
+//
+// StemWords returns the stems of words, in the same order.
+//
+func (s *RuleStemmer) StemWords(words []string) []string {
+	var buf [64]byte
+	b := buf[:0]
+	stems := make([]string, len(words))
+	for i, word := range words {
+		stems[i], b = s.stemString(b[:0], word)
+	}
+	return stems
+}
+
+//
+// stemString stems word using buf as scratch space. It returns the stem along
+// with the scratch buffer, which may have grown, so that it can be reused.
+//
+func (s *RuleStemmer) stemString(buf []byte, word string) (string, []byte) {
+	buf = appendLowerString(buf, strings.TrimSpace(word))
+	buf = s.run(buf, nil)
+	if string(buf) == word {
+		return word, buf
+	}
+	return string(buf), buf
+}
+
+//
+// StemTrace is StemTrace for the rules of s.
+//
+func (s *RuleStemmer) StemTrace(word []byte) (stem []byte, steps []StepResult) {
+	t := &trace{pending: -1}
+	stem = appendLower(make([]byte, 0, len(word)), bytes.TrimSpace(word))
+	stem = s.run(stem, t)
+	t.done(stem)
+	return stem, t.steps
+}
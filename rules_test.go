@@ -0,0 +1,178 @@
+package stemmer
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCondition(t *testing.T) {
+	fixtures := []string{
+		"",
+		"m>0",
+		"m>1",
+		"m=1 and *o",
+		"m=1 and not *o",
+		"*v*",
+		"*d",
+		"*d and not (*L or *S or *Z)",
+		"*[lsz]",
+		"m>1 and (*S or *T)",
+		"m>1 and (*S or *T)",
+		"m>=2 or m<1",
+	}
+
+	stems := []word{
+		[]byte("tr"),
+		[]byte("tr"),
+		[]byte("trouble"),
+		[]byte("fil"),
+		[]byte("fil"),
+		[]byte("sk"),
+		[]byte("hopp"),
+		[]byte("hiss"),
+		[]byte("hiss"),
+		[]byte("adopt"),
+		[]byte("ceremon"),
+		[]byte("oats"),
+	}
+
+	expected := []bool{
+		true,
+		false,
+		false,
+		true,
+		false,
+		false,
+		true,
+		false,
+		true,
+		true,
+		false,
+		false,
+	}
+
+	for k, value := range fixtures {
+		c, err := parseCondition(value)
+		if err != nil {
+			t.Errorf("parseCondition() returned an error, pass: '%s' error: '%v'", value, err)
+			continue
+		}
+		if result := c.eval(stems[k]); result != expected[k] {
+			t.Errorf("condition.eval() return value not what was expected, pass: '%s' stem: '%s' return: '%v' expected: '%v'", value, stems[k], result, expected[k])
+		}
+	}
+}
+
+func TestConditionError(t *testing.T) {
+	fixtures := []string{
+		"m>",
+		"m 1",
+		"(*v*",
+		"*v* and",
+		"*[]",
+		"*v* *d",
+		"#",
+	}
+
+	offsets := []int{
+		2,
+		2,
+		4,
+		7,
+		0,
+		4,
+		0,
+	}
+
+	for k, value := range fixtures {
+		_, err := parseCondition(value)
+		e, ok := err.(*ConditionError)
+		if !ok {
+			t.Errorf("parseCondition() did not return a *ConditionError, pass: '%s' return: '%v'", value, err)
+			continue
+		}
+		if e.Offset != offsets[k] {
+			t.Errorf("parseCondition() offset not what was expected, pass: '%s' return: '%d' expected: '%d'", value, e.Offset, offsets[k])
+		}
+	}
+}
+
+func TestNewRuleStemmer(t *testing.T) {
+	steps := append([]Step(nil), PorterSteps...)
+	steps[0] = Step{
+		Name: "1a",
+		Rules: append([]Rule{
+			{Suffix: "ae", Replacement: "a", Condition: "m>0"},
+		}, PorterStep1a.Rules...),
+	}
+	s, err := NewRuleStemmer("latin", steps)
+	if err != nil {
+		t.Fatalf("NewRuleStemmer() returned an error: '%v'", err)
+	}
+
+	fixtures := []word{
+		[]byte("vertebrae"),
+		[]byte("caresses"),
+	}
+
+	stemmed := []word{
+		[]byte("vertebra"),
+		[]byte("caress"),
+	}
+
+	for k, value := range fixtures {
+		if result := s.Stem(value); !bytes.Equal(result, stemmed[k]) {
+			t.Errorf("RuleStemmer.Stem() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, stemmed[k])
+		}
+	}
+}
+
+func TestNewRuleStemmerError(t *testing.T) {
+	loop := Step{Name: "1"}
+	loop.Rules = []Rule{{Suffix: "ss", Replacement: "ss", Then: &loop}}
+	a, b := Step{Name: "a"}, Step{Name: "b"}
+	a.Rules = []Rule{{Suffix: "es", Then: &b}}
+	b.Rules = []Rule{{Suffix: "e", Then: &a}}
+
+	fixtures := [][]Step{
+		{{Name: "1", Rules: []Rule{{Suffix: "s"}, {Suffix: "s"}}}},
+		{{Name: "1", Rules: []Rule{{Suffix: "ss", Replacement: "ss"}, {Suffix: "SS"}}}},
+		{{Name: "1", Rules: []Rule{{Suffix: "s", Condition: "m>"}}}},
+		{loop},
+		{{Name: "1", Rules: []Rule{{Suffix: "s", Then: &a}}}},
+	}
+
+	for _, value := range fixtures {
+		if _, err := NewRuleStemmer("broken", value); err == nil {
+			t.Errorf("NewRuleStemmer() did not return an error, pass: '%+v'", value)
+		}
+	}
+}
+
+func TestLongestSuffix(t *testing.T) {
+	s := MustNewRuleStemmer("test", []Step{{
+		Name: "1",
+		Rules: []Rule{
+			{Suffix: "s", Replacement: ""},
+			{Suffix: "ies", Replacement: "y", Condition: "m>0"},
+		},
+	}})
+
+	fixtures := []word{
+		[]byte("cats"),
+		[]byte("ponies"),
+		[]byte("ties"),
+	}
+
+	stemmed := []word{
+		[]byte("cat"),
+		[]byte("pony"),
+		[]byte("ties"),
+	}
+
+	for k, value := range fixtures {
+		if result := s.Stem(value); !bytes.Equal(result, stemmed[k]) {
+			t.Errorf("RuleStemmer.Stem() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, stemmed[k])
+		}
+	}
+}
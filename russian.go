@@ -0,0 +1,115 @@
+package stemmer
+
+//
+// The Russian stemmer:
+//
+//    https://snowballstem.org/algorithms/russian/stemmer.html
+//
+// All of the suffixes are removed in RV, the part of the word after its first
+// vowel.
+//
+
+const russianVowels = "аеиоуыэюя"
+
+var (
+	russianGerunds = newSuffixList(
+		"в", "вши", "вшись",
+		"ив", "ивши", "ившись", "ыв", "ывши", "ывшись",
+	)
+	russianAdjectives = newSuffixList(
+		"ее", "ие", "ые", "ое", "ими", "ыми", "ей", "ий", "ый", "ой", "ем", "им",
+		"ым", "ом", "его", "ого", "ему", "ому", "их", "ых", "ую", "юю", "ая",
+		"яя", "ою", "ею",
+	)
+	russianParticiples = newSuffixList(
+		"ем", "нн", "вш", "ющ", "щ",
+		"ивш", "ывш", "ующ",
+	)
+	russianReflexives = newSuffixList("ся", "сь")
+	russianVerbs      = newSuffixList(
+		"ла", "на", "ете", "йте", "ли", "й", "л", "ем", "н", "ло", "но", "ет",
+		"ют", "ны", "ть", "ешь", "нно",
+		"ила", "ыла", "ена", "ейте", "уйте", "ите", "или", "ыли", "ей", "уй",
+		"ил", "ыл", "им", "ым", "ен", "ило", "ыло", "ено", "ят", "ует", "уют",
+		"ит", "ыт", "ены", "ить", "ыть", "ишь", "ую", "ю",
+	)
+	russianNouns = newSuffixList(
+		"а", "ев", "ов", "ие", "ье", "е", "иями", "ями", "ами", "еи", "ии", "и",
+		"ией", "ей", "ой", "ий", "й", "иям", "ям", "ием", "ем", "ам", "ом", "о",
+		"у", "ах", "иях", "ях", "ы", "ь", "ию", "ью", "ю", "ия", "ья", "я",
+	)
+)
+
+func stemRussian(w []rune) []rune {
+	rv := len(w)
+	for i := range w {
+		if inGroup(w, i, russianVowels) {
+			rv = i + 1
+			break
+		}
+	}
+	r2 := regionAfter(w, regionAfter(w, 0, russianVowels), russianVowels)
+
+	// Step 1: a perfective gerund; otherwise a reflexive suffix, and then an
+	// adjectival ending, a verb ending or a noun ending.
+	var ok bool
+	if w, ok = russianAfterA(w, rv, russianGerunds, "в", "вши", "вшись"); !ok {
+		if s, start := russianReflexives.find(w, rv); s != "" {
+			w = w[:start]
+		}
+		if s, start := russianAdjectives.find(w, rv); s != "" {
+			w, _ = russianAfterA(w[:start], rv, russianParticiples, "ем", "нн", "вш", "ющ", "щ")
+		} else if w, ok = russianAfterA(w, rv, russianVerbs,
+			"ла", "на", "ете", "йте", "ли", "й", "л", "ем", "н", "ло", "но", "ет",
+			"ют", "ны", "ть", "ешь", "нно"); !ok {
+			if s, start := russianNouns.find(w, rv); s != "" {
+				w = w[:start]
+			}
+		}
+	}
+
+	// Step 2: и.
+	if n := len(w); n-1 >= rv && w[n-1] == 'и' {
+		w = w[:n-1]
+	}
+
+	// Step 3: the derivational suffixes ост and ость in R2.
+	if s, start := newSuffixList("ост", "ость").find(w, rv); s != "" && start >= r2 {
+		w = w[:start]
+	}
+
+	// Step 4: ейш or ейше is removed, and нн undoubled; a final н after н is
+	// removed; a final ь is removed.
+	switch s, start := newSuffixList("ейш", "ейше", "н", "ь").find(w, rv); s {
+	case "ейш", "ейше":
+		w = w[:start]
+		if n := len(w); n-2 >= rv && endsWith(w, "нн") {
+			w = w[:n-1]
+		}
+	case "н":
+		if start-1 >= rv && w[start-1] == 'н' {
+			w = w[:start]
+		}
+	case "ь":
+		w = w[:start]
+	}
+	return w
+}
+
+//
+// russianAfterA removes the longest suffix of w in l in RV. The suffixes of
+// afterA are removed only after an а or я in RV. It reports whether it
+// removed a suffix.
+//
+func russianAfterA(w []rune, rv int, l suffixList, afterA ...string) ([]rune, bool) {
+	s, start := l.find(w, rv)
+	if s == "" {
+		return w, false
+	}
+	for _, a := range afterA {
+		if s == a && !(start-1 >= rv && inGroup(w, start-1, "ая")) {
+			return w, false
+		}
+	}
+	return w[:start], true
+}
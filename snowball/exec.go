@@ -0,0 +1,541 @@
+package snowball
+
+import (
+	"errors"
+	"math"
+)
+
+//
+// env is the state of a program running on a word: the word, the cursor c,
+// the limits lb and l, the slice bra..ket and the variables. In backward mode
+// the cursor moves from l towards lb.
+//
+type env struct {
+	p        *Program
+	word     []rune
+	c, l, lb int
+	bra, ket int
+	ints     []int
+	bools    []bool
+	strs     [][]rune
+	amongs   []int
+}
+
+//
+// fault is the panic that stops a program which makes an error; call turns it
+// back into an *Error.
+//
+type fault struct {
+	line int
+	err  error
+}
+
+func newEnv(p *Program, word []rune) *env {
+	return &env{
+		p:      p,
+		word:   word,
+		l:      len(word),
+		ket:    len(word),
+		ints:   make([]int, p.integers),
+		bools:  make([]bool, p.booleans),
+		strs:   make([][]rune, p.strings),
+		amongs: make([]int, p.amongs),
+	}
+}
+
+func (e *env) fail(line int, msg string) {
+	panic(fault{line, errors.New(msg)})
+}
+
+//
+// call runs the routine r from the start of the word.
+//
+func (e *env) call(r *routine) (ok bool, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			f, isFault := v.(fault)
+			if !isFault {
+				panic(v)
+			}
+			err = &Error{File: e.p.file, Line: f.line, Err: f.err}
+		}
+	}()
+	if r.backward {
+		e.lb, e.c = 0, e.l
+	}
+	return e.exec(r.body), nil
+}
+
+//
+// save returns the cursor in a form that survives changes to the word at the
+// other end: from the start in forward mode, from the limit in backward mode.
+//
+func (e *env) save(n *node) int {
+	if n.backward {
+		return e.l - e.c
+	}
+	return e.c
+}
+
+func (e *env) restore(n *node, c int) {
+	if n.backward {
+		e.c = e.l - c
+	} else {
+		e.c = c
+	}
+}
+
+//
+// exec runs the command n and returns whether it succeeded.
+//
+func (e *env) exec(n *node) bool {
+	switch n.op {
+	case opSeq:
+		for _, c := range n.list {
+			if !e.exec(c) {
+				return false
+			}
+		}
+		return true
+	case opOr:
+		c := e.save(n)
+		for _, alt := range n.list {
+			if e.exec(alt) {
+				return true
+			}
+			e.restore(n, c)
+		}
+		return false
+	case opAnd:
+		c := e.save(n)
+		for i, alt := range n.list {
+			if i > 0 {
+				e.restore(n, c)
+			}
+			if !e.exec(alt) {
+				return false
+			}
+		}
+		return true
+	case opNot:
+		c := e.save(n)
+		if e.exec(n.list[0]) {
+			return false
+		}
+		e.restore(n, c)
+		return true
+	case opTest:
+		c := e.save(n)
+		if !e.exec(n.list[0]) {
+			return false
+		}
+		e.restore(n, c)
+		return true
+	case opTry:
+		c := e.save(n)
+		if !e.exec(n.list[0]) {
+			e.restore(n, c)
+		}
+		return true
+	case opDo:
+		c := e.save(n)
+		e.exec(n.list[0])
+		e.restore(n, c)
+		return true
+	case opFail:
+		e.exec(n.list[0])
+		return false
+	case opGoto, opGopast:
+		for {
+			c := e.save(n)
+			if e.exec(n.list[0]) {
+				if n.op == opGoto {
+					e.restore(n, c)
+				}
+				return true
+			}
+			e.restore(n, c)
+			if !e.move(n, 1) {
+				return false
+			}
+		}
+	case opRepeat:
+		e.repeat(n)
+		return true
+	case opLoop:
+		for i := e.eval(n, n.expr); i > 0; i-- {
+			if !e.exec(n.list[0]) {
+				return false
+			}
+		}
+		return true
+	case opAtleast:
+		for i := e.eval(n, n.expr); i > 0; i-- {
+			if !e.exec(n.list[0]) {
+				return false
+			}
+		}
+		e.repeat(n)
+		return true
+	case opBackwards, opReverse:
+		// n is in the mode around the command, the opposite of the mode
+		// its command runs in.
+		lb, l := e.lb, e.l
+		if n.backward {
+			e.l, e.c = e.c, e.lb
+		} else {
+			e.lb, e.c = e.c, e.l
+		}
+		ok := e.exec(n.list[0])
+		if n.backward {
+			e.c, e.l = e.l, l
+		} else {
+			e.c, e.lb = e.lb, lb
+		}
+		return ok
+	case opSetlimit:
+		c := e.save(n)
+		if !e.exec(n.list[0]) {
+			return false
+		}
+		var ok bool
+		if n.backward {
+			lb := e.lb
+			e.lb = e.c
+			e.restore(n, c)
+			ok = e.exec(n.list[1])
+			e.lb = lb
+		} else {
+			rest := e.l - e.c
+			e.l = e.c
+			e.restore(n, c)
+			ok = e.exec(n.list[1])
+			e.l += rest
+		}
+		return ok
+	case opTrue, opDebug:
+		return true
+	case opFalse:
+		return false
+	case opLiteral:
+		return e.eq(n, n.str)
+	case opStrVar:
+		return e.eq(n, e.strs[n.v])
+	case opGrouping, opNonGrouping:
+		var r rune
+		if n.backward {
+			if e.c <= e.lb {
+				return false
+			}
+			r = e.word[e.c-1]
+		} else {
+			if e.c >= e.l {
+				return false
+			}
+			r = e.word[e.c]
+		}
+		if n.set.has(r) != (n.op == opGrouping) {
+			return false
+		}
+		return e.move(n, 1)
+	case opCall:
+		return e.exec(n.routine.body)
+	case opNext:
+		return e.move(n, 1)
+	case opHop:
+		i := e.eval(n, n.expr)
+		return i >= 0 && e.move(n, i)
+	case opAtlimit:
+		if n.backward {
+			return e.c == e.lb
+		}
+		return e.c == e.l
+	case opTolimit:
+		if n.backward {
+			e.c = e.lb
+		} else {
+			e.c = e.l
+		}
+		return true
+	case opSetmark:
+		e.ints[n.v] = e.c
+		return true
+	case opTomark:
+		i := e.eval(n, n.expr)
+		if n.backward && (e.c < i || i < e.lb) || !n.backward && (e.c > i || i > e.l) {
+			return false
+		}
+		e.c = i
+		return true
+	case opAtmark:
+		return e.c == e.eval(n, n.expr)
+	case opBra:
+		if n.backward {
+			e.ket = e.c
+		} else {
+			e.bra = e.c
+		}
+		return true
+	case opKet:
+		if n.backward {
+			e.bra = e.c
+		} else {
+			e.ket = e.c
+		}
+		return true
+	case opSlice, opSliceVar, opDelete:
+		s := n.str
+		if n.op == opSliceVar {
+			s = e.strs[n.v]
+		}
+		e.checkSlice(n)
+		e.replace(e.bra, e.ket, s)
+		e.ket = e.bra + len(s)
+		return true
+	case opInsert, opInsertVar, opAttach, opAttachVar:
+		s := n.str
+		if n.op == opInsertVar || n.op == opAttachVar {
+			s = e.strs[n.v]
+		}
+		c := e.c
+		e.replace(c, c, s)
+		if c <= e.bra {
+			e.bra += len(s)
+		}
+		if c <= e.ket {
+			e.ket += len(s)
+		}
+		attach := n.op == opAttach || n.op == opAttachVar
+		if n.backward != attach {
+			e.c = c
+		}
+		return true
+	case opSliceTo:
+		e.checkSlice(n)
+		e.strs[n.v] = append([]rune(nil), e.word[e.bra:e.ket]...)
+		return true
+	case opAssignTo:
+		e.strs[n.v] = append([]rune(nil), e.word[:e.l]...)
+		return true
+	case opSubstring:
+		e.amongs[n.among.id] = e.find(n, n.among)
+		return e.amongs[n.among.id] != 0
+	case opAmong:
+		return e.among(n)
+	case opSet, opUnset:
+		e.bools[n.v] = n.op == opSet
+		return true
+	case opBoolean:
+		return e.bools[n.v]
+	case opIntAssign:
+		i := e.eval(n, n.expr)
+		switch n.cmp {
+		case "=":
+			e.ints[n.v] = i
+		case "+=":
+			e.ints[n.v] += i
+		case "-=":
+			e.ints[n.v] -= i
+		case "*=":
+			e.ints[n.v] *= i
+		case "/=":
+			if i == 0 {
+				e.fail(n.line, "division by zero")
+			}
+			e.ints[n.v] /= i
+		}
+		return true
+	case opIntCompare:
+		a, b := e.eval(n, n.left), e.eval(n, n.expr)
+		switch n.cmp {
+		case "==":
+			return a == b
+		case "!=":
+			return a != b
+		case "<":
+			return a < b
+		case "<=":
+			return a <= b
+		case ">":
+			return a > b
+		}
+		return a >= b
+	}
+	e.fail(n.line, "unknown command")
+	return false
+}
+
+//
+// repeat runs the command of n until it fails, restoring the cursor the
+// failure leaves.
+//
+func (e *env) repeat(n *node) {
+	for {
+		c := e.save(n)
+		if !e.exec(n.list[0]) {
+			e.restore(n, c)
+			return
+		}
+	}
+}
+
+//
+// move moves the cursor i characters in the direction of n, failing if that
+// crosses the limit.
+//
+func (e *env) move(n *node, i int) bool {
+	if n.backward {
+		if e.c-i < e.lb {
+			return false
+		}
+		e.c -= i
+		return true
+	}
+	if e.c+i > e.l {
+		return false
+	}
+	e.c += i
+	return true
+}
+
+//
+// eq tests whether s is at the cursor and moves over it.
+//
+func (e *env) eq(n *node, s []rune) bool {
+	if n.backward {
+		if e.c-e.lb < len(s) || !equal(e.word[e.c-len(s):e.c], s) {
+			return false
+		}
+		e.c -= len(s)
+		return true
+	}
+	if e.l-e.c < len(s) || !equal(e.word[e.c:e.c+len(s)], s) {
+		return false
+	}
+	e.c += len(s)
+	return true
+}
+
+func equal(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *env) checkSlice(n *node) {
+	if e.bra < 0 || e.bra > e.ket || e.ket > e.l {
+		e.fail(n.line, "slice outside the word")
+	}
+}
+
+//
+// replace replaces the characters from i to j by s, moving the limit and the
+// cursor with the characters after them.
+//
+func (e *env) replace(i, j int, s []rune) {
+	adjust := len(s) - (j - i)
+	word := make([]rune, 0, len(e.word)+adjust)
+	word = append(append(append(word, e.word[:i]...), s...), e.word[j:]...)
+	e.word = word
+	e.l += adjust
+	if e.c >= j {
+		e.c += adjust
+	} else if e.c > i {
+		e.c = i
+	}
+}
+
+//
+// find returns the group of the longest string of a at the cursor whose
+// routine succeeds, and moves over it, or returns 0.
+//
+func (e *env) find(n *node, a *among) int {
+	c := e.c
+	for _, s := range a.strings {
+		e.c = c
+		if !e.eq(n, s.s) {
+			continue
+		}
+		if s.routine != nil {
+			after := e.c
+			ok := e.exec(s.routine.body)
+			e.c = after
+			if !ok {
+				continue
+			}
+		}
+		return s.group + 1
+	}
+	e.c = c
+	return 0
+}
+
+//
+// among runs the commands a string of the among of n selects: the one found
+// by its substring or, without one, at the cursor.
+//
+func (e *env) among(n *node) bool {
+	a := n.among
+	group := e.amongs[a.id]
+	if !a.linked {
+		group = e.find(n, a)
+	}
+	if group == 0 {
+		return false
+	}
+	if a.prelude != nil && !e.exec(a.prelude) {
+		return false
+	}
+	if c := a.commands[group-1]; c != nil {
+		return e.exec(c)
+	}
+	return true
+}
+
+//
+// eval returns the value of the integer expression x.
+//
+func (e *env) eval(n *node, x *expr) int {
+	switch x.op {
+	case "num":
+		return x.n
+	case "var":
+		return e.ints[x.v]
+	case "cursor":
+		return e.c
+	case "limit":
+		if n.backward {
+			return e.lb
+		}
+		return e.l
+	case "size", "len":
+		return len(e.word)
+	case "sizeof":
+		return len(e.strs[x.v])
+	case "maxint":
+		return math.MaxInt32
+	case "minint":
+		return math.MinInt32
+	case "neg":
+		return -e.eval(n, x.left)
+	}
+	a, b := e.eval(n, x.left), e.eval(n, x.right)
+	switch x.op {
+	case "+":
+		return a + b
+	case "-":
+		return a - b
+	case "*":
+		return a * b
+	}
+	if b == 0 {
+		e.fail(n.line, "division by zero")
+	}
+	return a / b
+}
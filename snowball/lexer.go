@@ -0,0 +1,300 @@
+package snowball
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokName
+	tokString
+	tokNumber
+	tokOp
+)
+
+//
+// token is a token of a Snowball source: a name, which may be a keyword, a
+// string literal with its escapes replaced, a number or an operator.
+//
+type token struct {
+	kind tokenKind
+	text string
+	str  []rune
+	num  int
+	line int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of file"
+	case tokString:
+		return "'" + string(t.str) + "'"
+	}
+	return "'" + t.text + "'"
+}
+
+//
+// operators are the operators of Snowball, longest first so that the lexer
+// picks the longest one.
+//
+var operators = []string{
+	"==", "!=", "<=", ">=", "+=", "-=", "*=", "/=", "<-", "<+", "->", "=>",
+	"(", ")", "[", "]", "$", "?", "=", "<", ">", "+", "-", "*", "/",
+}
+
+//
+// lexer splits a Snowball source into tokens. Strings are decoded as they are
+// read, so that the string escapes and definitions that come before them in
+// the source apply.
+//
+type lexer struct {
+	file    string
+	src     string
+	pos     int
+	line    int
+	escapes string
+	defs    map[string][]rune
+	peeked  *token
+}
+
+func newLexer(file, src string) *lexer {
+	return &lexer{file: file, src: src, line: 1, defs: make(map[string][]rune)}
+}
+
+func (l *lexer) errorf(line int, format string, args ...interface{}) error {
+	return &Error{File: l.file, Line: line, Err: fmt.Errorf(format, args...)}
+}
+
+//
+// peek returns the next token without consuming it.
+//
+func (l *lexer) peek() (token, error) {
+	if l.peeked == nil {
+		t, err := l.scan()
+		if err != nil {
+			return t, err
+		}
+		l.peeked = &t
+	}
+	return *l.peeked, nil
+}
+
+//
+// next consumes and returns the next token.
+//
+func (l *lexer) next() (token, error) {
+	t, err := l.peek()
+	l.peeked = nil
+	return t, err
+}
+
+//
+// skip skips white space and comments.
+//
+func (l *lexer) skip() error {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; {
+		case c == '\n':
+			l.line++
+			l.pos++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f':
+			l.pos++
+		case strings.HasPrefix(l.src[l.pos:], "//"):
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.src[l.pos:], "/*"):
+			end := strings.Index(l.src[l.pos+2:], "*/")
+			if end < 0 {
+				return l.errorf(l.line, "unterminated comment")
+			}
+			l.line += strings.Count(l.src[l.pos:l.pos+2+end], "\n")
+			l.pos += end + 4
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (l *lexer) scan() (token, error) {
+	if err := l.skip(); err != nil {
+		return token{}, err
+	}
+	t := token{line: l.line}
+	if l.pos == len(l.src) {
+		return t, nil
+	}
+	switch c := l.src[l.pos]; {
+	case isLetter(c):
+		start := l.pos
+		for l.pos < len(l.src) && (isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		t.kind, t.text = tokName, l.src[start:l.pos]
+		if t.text == "stringdef" {
+			return t, l.stringdef()
+		}
+		if t.text == "stringescapes" {
+			return t, l.stringescapes()
+		}
+	case isDigit(c):
+		start := l.pos
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.pos++
+		}
+		n, err := strconv.Atoi(l.src[start:l.pos])
+		if err != nil {
+			return t, l.errorf(t.line, "bad number %s", l.src[start:l.pos])
+		}
+		t.kind, t.text, t.num = tokNumber, l.src[start:l.pos], n
+	case c == '\'':
+		s, err := l.literal()
+		if err != nil {
+			return t, err
+		}
+		t.kind, t.str = tokString, s
+	default:
+		for _, op := range operators {
+			if strings.HasPrefix(l.src[l.pos:], op) {
+				l.pos += len(op)
+				t.kind, t.text = tokOp, op
+				return t, nil
+			}
+		}
+		r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+		return t, l.errorf(t.line, "unexpected character %q", r)
+	}
+	return t, nil
+}
+
+//
+// literal reads a string literal, replacing its escapes.
+//
+func (l *lexer) literal() ([]rune, error) {
+	line := l.line
+	l.pos++
+	var s []rune
+	for {
+		if l.pos >= len(l.src) || l.src[l.pos] == '\n' {
+			return nil, l.errorf(line, "unterminated string")
+		}
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		l.pos += size
+		switch {
+		case r == '\'':
+			return s, nil
+		case len(l.escapes) == 2 && r == rune(l.escapes[0]):
+			end := strings.IndexByte(l.src[l.pos:], l.escapes[1])
+			if end < 0 {
+				return nil, l.errorf(line, "unterminated escape")
+			}
+			name := l.src[l.pos : l.pos+end]
+			l.pos += end + 1
+			e, err := l.escape(name)
+			if err != nil {
+				return nil, l.errorf(line, "%v", err)
+			}
+			s = append(s, e...)
+		default:
+			s = append(s, r)
+		}
+	}
+}
+
+//
+// escape returns the characters of the escape {name}: a quote, the start of
+// an escape, U+ and a code point in hexadecimal, or a stringdef.
+//
+func (l *lexer) escape(name string) ([]rune, error) {
+	switch {
+	case name == "'" || name == l.escapes[:1]:
+		return []rune(name), nil
+	case strings.HasPrefix(name, "U+"):
+		n, err := strconv.ParseUint(name[2:], 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("bad escape {%s}", name)
+		}
+		return []rune{rune(n)}, nil
+	}
+	if s, ok := l.defs[name]; ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("undefined escape {%s}", name)
+}
+
+//
+// stringescapes reads the two characters that start and end escapes.
+//
+func (l *lexer) stringescapes() error {
+	line := l.line
+	if err := l.skip(); err != nil {
+		return err
+	}
+	if l.pos+2 > len(l.src) {
+		return l.errorf(line, "stringescapes needs two characters")
+	}
+	l.escapes = l.src[l.pos : l.pos+2]
+	l.pos += 2
+	return nil
+}
+
+//
+// stringdef reads the definition of an escape: its name, and a string or the
+// hex or decimal code points of its characters.
+//
+func (l *lexer) stringdef() error {
+	line := l.line
+	if err := l.skip(); err != nil {
+		return err
+	}
+	start := l.pos
+	for l.pos < len(l.src) && !isSpace(l.src[l.pos]) {
+		l.pos++
+	}
+	name := l.src[start:l.pos]
+	if name == "" {
+		return l.errorf(line, "stringdef needs a name")
+	}
+	t, err := l.scan()
+	if err != nil {
+		return err
+	}
+	base := 0
+	if t.kind == tokName && (t.text == "hex" || t.text == "decimal") {
+		base = 16
+		if t.text == "decimal" {
+			base = 10
+		}
+		if t, err = l.scan(); err != nil {
+			return err
+		}
+	}
+	if t.kind != tokString {
+		return l.errorf(line, "stringdef %s needs a string", name)
+	}
+	s := t.str
+	if base != 0 {
+		s = nil
+		for _, f := range strings.Fields(string(t.str)) {
+			n, err := strconv.ParseUint(f, base, 32)
+			if err != nil {
+				return l.errorf(line, "stringdef %s: %v", name, errors.Unwrap(err))
+			}
+			s = append(s, rune(n))
+		}
+	}
+	l.defs[name] = s
+	return nil
+}
+
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+func isSpace(c byte) bool  { return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' }
@@ -0,0 +1,961 @@
+package snowball
+
+import "sort"
+
+type op int
+
+const (
+	opSeq op = iota
+	opOr
+	opAnd
+	opNot
+	opTest
+	opTry
+	opDo
+	opFail
+	opGoto
+	opGopast
+	opRepeat
+	opLoop
+	opAtleast
+	opBackwards
+	opReverse
+	opSetlimit
+	opTrue
+	opFalse
+	opLiteral
+	opStrVar
+	opGrouping
+	opNonGrouping
+	opCall
+	opNext
+	opHop
+	opAtlimit
+	opTolimit
+	opSetmark
+	opTomark
+	opAtmark
+	opBra
+	opKet
+	opSlice
+	opSliceVar
+	opInsert
+	opInsertVar
+	opAttach
+	opAttachVar
+	opDelete
+	opSliceTo
+	opAssignTo
+	opSubstring
+	opAmong
+	opSet
+	opUnset
+	opBoolean
+	opIntAssign
+	opIntCompare
+	opDebug
+)
+
+//
+// node is a command of a Snowball program. Which fields are used depends on
+// its op; backward is whether it runs in backward mode.
+//
+type node struct {
+	op       op
+	backward bool
+	list     []*node
+	str      []rune
+	v        int
+	set      *grouping
+	routine  *routine
+	among    *among
+	expr     *expr
+	cmp      string
+	left     *expr
+	line     int
+}
+
+//
+// among is an among command: its strings, the routine each one needs and
+// which group of commands each one selects.
+//
+type among struct {
+	id       int
+	linked   bool
+	strings  []amongString
+	commands []*node
+	prelude  *node
+}
+
+type amongString struct {
+	s       []rune
+	routine *routine
+	group   int
+}
+
+//
+// routine is a routine or external of a program.
+//
+type routine struct {
+	name     string
+	body     *node
+	backward bool
+	defined  bool
+	external bool
+	line     int
+}
+
+//
+// grouping is a set of characters.
+//
+type grouping struct {
+	ascii [128]bool
+	other map[rune]bool
+}
+
+func (g *grouping) add(r rune) {
+	if r < 128 {
+		g.ascii[r] = true
+		return
+	}
+	if g.other == nil {
+		g.other = make(map[rune]bool)
+	}
+	g.other[r] = true
+}
+
+func (g *grouping) remove(r rune) {
+	if r < 128 {
+		g.ascii[r] = false
+		return
+	}
+	delete(g.other, r)
+}
+
+func (g *grouping) has(r rune) bool {
+	if r < 128 {
+		return r >= 0 && g.ascii[r]
+	}
+	return g.other[r]
+}
+
+//
+// expr is an integer expression.
+//
+type expr struct {
+	op          string
+	left, right *expr
+	n           int
+	v           int
+}
+
+type kind int
+
+const (
+	kindString kind = iota + 1
+	kindInteger
+	kindBoolean
+	kindRoutine
+	kindGrouping
+)
+
+var kindNames = map[string]kind{
+	"strings":   kindString,
+	"integers":  kindInteger,
+	"booleans":  kindBoolean,
+	"routines":  kindRoutine,
+	"externals": kindRoutine,
+	"groupings": kindGrouping,
+}
+
+type symbol struct {
+	kind     kind
+	v        int
+	routine  *routine
+	grouping *grouping
+	defined  bool
+}
+
+//
+// parser reads the declarations and definitions of a program.
+//
+type parser struct {
+	*lexer
+	prog      *Program
+	symbols   map[string]*symbol
+	backward  bool
+	substring *node
+	calls     []*node
+}
+
+//
+// parse parses the Snowball source of a program.
+//
+func parse(file, src string) (*Program, error) {
+	p := &parser{
+		lexer:   newLexer(file, src),
+		prog:    &Program{routines: make(map[string]*routine)},
+		symbols: make(map[string]*symbol),
+	}
+	for {
+		t, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if t.kind == tokEOF {
+			break
+		}
+		if err := p.topLevel(t); err != nil {
+			return nil, err
+		}
+	}
+	return p.prog, p.check()
+}
+
+func (p *parser) topLevel(t token) error {
+	if t.kind != tokName {
+		return p.errorf(t.line, "unexpected %v", t)
+	}
+	if k, ok := kindNames[t.text]; ok {
+		return p.declare(k, t.text == "externals")
+	}
+	switch t.text {
+	case "stringdef", "stringescapes":
+		return nil
+	case "define":
+		return p.define()
+	case "backwardmode":
+		if err := p.expect("("); err != nil {
+			return err
+		}
+		p.backward = true
+		defer func() { p.backward = false }()
+		for {
+			t, err := p.next()
+			if err != nil {
+				return err
+			}
+			if t.kind == tokOp && t.text == ")" {
+				return nil
+			}
+			if t.kind != tokName || t.text != "define" {
+				return p.errorf(t.line, "expected define, found %v", t)
+			}
+			if err := p.define(); err != nil {
+				return err
+			}
+		}
+	}
+	return p.errorf(t.line, "unexpected %v", t)
+}
+
+//
+// declare reads the names of a declaration.
+//
+func (p *parser) declare(k kind, external bool) error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	for {
+		t, err := p.next()
+		if err != nil {
+			return err
+		}
+		if t.kind == tokOp && t.text == ")" {
+			return nil
+		}
+		if t.kind != tokName || keywords[t.text] {
+			return p.errorf(t.line, "expected a name, found %v", t)
+		}
+		if _, ok := p.symbols[t.text]; ok {
+			return p.errorf(t.line, "%s declared twice", t.text)
+		}
+		s := &symbol{kind: k}
+		switch k {
+		case kindString:
+			s.v = p.prog.strings
+			p.prog.strings++
+		case kindInteger:
+			s.v = p.prog.integers
+			p.prog.integers++
+		case kindBoolean:
+			s.v = p.prog.booleans
+			p.prog.booleans++
+		case kindRoutine:
+			s.routine = &routine{name: t.text, external: external, line: t.line}
+			p.prog.routines[t.text] = s.routine
+		case kindGrouping:
+			s.grouping = &grouping{}
+		}
+		p.symbols[t.text] = s
+	}
+}
+
+//
+// define reads the definition of a routine or a grouping.
+//
+func (p *parser) define() error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	s, err := p.lookup(t)
+	if err != nil {
+		return err
+	}
+	if s.defined {
+		return p.errorf(t.line, "%s defined twice", t.text)
+	}
+	s.defined = true
+	switch s.kind {
+	case kindRoutine:
+		if err := p.expectName("as"); err != nil {
+			return err
+		}
+		r := s.routine
+		r.backward, r.defined = p.backward, true
+		p.substring = nil
+		r.body, err = p.command()
+		if err == nil && p.substring != nil {
+			err = p.errorf(p.substring.line, "substring without among")
+		}
+		return err
+	case kindGrouping:
+		return p.groupingDef(s.grouping)
+	}
+	return p.errorf(t.line, "%s is not a routine or grouping", t.text)
+}
+
+//
+// groupingDef reads the strings and groupings added to and removed from a
+// grouping.
+//
+func (p *parser) groupingDef(g *grouping) error {
+	add := true
+	for {
+		t, err := p.next()
+		if err != nil {
+			return err
+		}
+		var chars []rune
+		switch t.kind {
+		case tokString:
+			chars = t.str
+		case tokName:
+			s, err := p.lookup(t)
+			if err != nil {
+				return err
+			}
+			if s.kind != kindGrouping || !s.defined || s.grouping == g {
+				return p.errorf(t.line, "%s is not a defined grouping", t.text)
+			}
+			for c := rune(0); c < 128; c++ {
+				if s.grouping.ascii[c] {
+					chars = append(chars, c)
+				}
+			}
+			for c := range s.grouping.other {
+				chars = append(chars, c)
+			}
+		default:
+			return p.errorf(t.line, "expected a string or grouping, found %v", t)
+		}
+		for _, c := range chars {
+			if add {
+				g.add(c)
+			} else {
+				g.remove(c)
+			}
+		}
+		if t, err = p.peek(); err != nil {
+			return err
+		}
+		if t.kind != tokOp || t.text != "+" && t.text != "-" {
+			return nil
+		}
+		p.next()
+		add = t.text == "+"
+	}
+}
+
+//
+// check makes sure that every routine is defined and called in the mode it
+// was defined in.
+//
+func (p *parser) check() error {
+	names := make([]string, 0, len(p.prog.routines))
+	for name := range p.prog.routines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		r := p.prog.routines[name]
+		if !r.defined {
+			return p.errorf(r.line, "routine %s is not defined", name)
+		}
+		if r.external {
+			p.prog.externals = append(p.prog.externals, name)
+		}
+	}
+	for _, n := range p.calls {
+		if n.routine.backward != n.backward {
+			return p.errorf(n.line, "routine %s called in the wrong mode", n.routine.name)
+		}
+	}
+	return nil
+}
+
+var keywords = map[string]bool{}
+
+func init() {
+	for _, k := range []string{
+		"among", "and", "as", "atleast", "atlimit", "atmark", "attach",
+		"backwardmode", "backwards", "booleans", "cursor", "decimal", "define",
+		"delete", "do", "externals", "fail", "false", "for", "get", "gopast",
+		"goto", "groupings", "hex", "hop", "insert", "integers", "len", "lenof",
+		"limit", "loop", "maxint", "minint", "next", "non", "not", "or",
+		"repeat", "reverse", "routines", "set", "setlimit", "setmark", "size",
+		"sizeof", "stringdef", "stringescapes", "strings", "substring", "test",
+		"tolimit", "tomark", "true", "try", "unset",
+	} {
+		keywords[k] = true
+	}
+}
+
+func (p *parser) lookup(t token) (*symbol, error) {
+	if t.kind != tokName || keywords[t.text] {
+		return nil, p.errorf(t.line, "expected a name, found %v", t)
+	}
+	s, ok := p.symbols[t.text]
+	if !ok {
+		return nil, p.errorf(t.line, "%s is not declared", t.text)
+	}
+	return s, nil
+}
+
+func (p *parser) expect(op string) error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if t.kind != tokOp || t.text != op {
+		return p.errorf(t.line, "expected '%s', found %v", op, t)
+	}
+	return nil
+}
+
+func (p *parser) expectName(name string) error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if t.kind != tokName || t.text != name {
+		return p.errorf(t.line, "expected %s, found %v", name, t)
+	}
+	return nil
+}
+
+func (p *parser) isOp(t token, ops ...string) bool {
+	if t.kind != tokOp {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+//
+// command reads a command with the commands joined to it by or and and,
+// which bind tighter than a sequence and from left to right.
+//
+func (p *parser) command() (*node, error) {
+	n, err := p.simple()
+	if err != nil {
+		return nil, err
+	}
+	var joined *node
+	for {
+		t, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if t.kind != tokName || t.text != "or" && t.text != "and" {
+			return n, nil
+		}
+		p.next()
+		right, err := p.simple()
+		if err != nil {
+			return nil, err
+		}
+		o := opOr
+		if t.text == "and" {
+			o = opAnd
+		}
+		if joined != nil && joined.op == o {
+			joined.list = append(joined.list, right)
+			continue
+		}
+		joined = &node{op: o, backward: p.backward, list: []*node{n, right}, line: t.line}
+		n = joined
+	}
+}
+
+//
+// simple reads a command that is not joined by or or and.
+//
+func (p *parser) simple() (*node, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	n := &node{backward: p.backward, line: t.line}
+	switch t.kind {
+	case tokString:
+		n.op, n.str = opLiteral, t.str
+		return n, nil
+	case tokOp:
+		return p.operator(t, n)
+	case tokName:
+		return p.keyword(t, n)
+	}
+	return nil, p.errorf(t.line, "expected a command, found %v", t)
+}
+
+func (p *parser) operator(t token, n *node) (*node, error) {
+	switch t.text {
+	case "(":
+		n.op = opSeq
+		for {
+			t, err := p.peek()
+			if err != nil {
+				return nil, err
+			}
+			if p.isOp(t, ")") {
+				p.next()
+				return n, nil
+			}
+			c, err := p.command()
+			if err != nil {
+				return nil, err
+			}
+			n.list = append(n.list, c)
+		}
+	case "[":
+		n.op = opBra
+		return n, nil
+	case "]":
+		n.op = opKet
+		return n, nil
+	case "?":
+		n.op = opDebug
+		return n, nil
+	case "<-":
+		return p.stringArg(n, opSlice, opSliceVar)
+	case "<+":
+		return p.stringArg(n, opInsert, opInsertVar)
+	case "->", "=>":
+		n.op = opSliceTo
+		if t.text == "=>" {
+			n.op = opAssignTo
+		}
+		return n, p.variable(n, kindString)
+	case "$":
+		return p.integerCommand(n)
+	}
+	return nil, p.errorf(t.line, "expected a command, found %v", t)
+}
+
+func (p *parser) keyword(t token, n *node) (*node, error) {
+	var err error
+	unary := map[string]op{
+		"not": opNot, "test": opTest, "try": opTry, "do": opDo, "fail": opFail,
+		"goto": opGoto, "gopast": opGopast, "repeat": opRepeat,
+	}
+	if o, ok := unary[t.text]; ok {
+		n.op = o
+		c, err := p.simple()
+		if err != nil {
+			return nil, err
+		}
+		n.list = []*node{c}
+		return n, nil
+	}
+	switch t.text {
+	case "backwards", "reverse":
+		if t.text == "backwards" && p.backward {
+			return nil, p.errorf(t.line, "backwards in backward mode")
+		}
+		n.op = opBackwards
+		if t.text == "reverse" {
+			n.op = opReverse
+		}
+		p.backward = !p.backward
+		c, err := p.simple()
+		p.backward = !p.backward
+		if err != nil {
+			return nil, err
+		}
+		n.list = []*node{c}
+	case "loop", "atleast":
+		n.op = opLoop
+		if t.text == "atleast" {
+			n.op = opAtleast
+		}
+		if n.expr, err = p.expr(); err != nil {
+			return nil, err
+		}
+		c, err := p.simple()
+		if err != nil {
+			return nil, err
+		}
+		n.list = []*node{c}
+	case "setlimit":
+		n.op = opSetlimit
+		limit, err := p.simple()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectName("for"); err != nil {
+			return nil, err
+		}
+		c, err := p.simple()
+		if err != nil {
+			return nil, err
+		}
+		n.list = []*node{limit, c}
+	case "hop", "tomark", "atmark":
+		n.op = map[string]op{"hop": opHop, "tomark": opTomark, "atmark": opAtmark}[t.text]
+		if n.expr, err = p.expr(); err != nil {
+			return nil, err
+		}
+	case "true", "false", "next", "atlimit", "tolimit", "delete":
+		n.op = map[string]op{
+			"true": opTrue, "false": opFalse, "next": opNext,
+			"atlimit": opAtlimit, "tolimit": opTolimit, "delete": opDelete,
+		}[t.text]
+	case "insert":
+		return p.stringArg(n, opInsert, opInsertVar)
+	case "attach":
+		return p.stringArg(n, opAttach, opAttachVar)
+	case "setmark":
+		n.op = opSetmark
+		return n, p.variable(n, kindInteger)
+	case "set", "unset":
+		n.op = opSet
+		if t.text == "unset" {
+			n.op = opUnset
+		}
+		return n, p.variable(n, kindBoolean)
+	case "non":
+		n.op = opNonGrouping
+		next, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if p.isOp(next, "-") {
+			p.next()
+		}
+		g, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		s, err := p.lookup(g)
+		if err != nil {
+			return nil, err
+		}
+		if s.kind != kindGrouping {
+			return nil, p.errorf(g.line, "%s is not a grouping", g.text)
+		}
+		n.set = s.grouping
+	case "substring":
+		if p.substring != nil {
+			return nil, p.errorf(t.line, "substring without among")
+		}
+		n.op = opSubstring
+		p.substring = n
+	case "among":
+		return p.among(n)
+	default:
+		s, err := p.lookup(t)
+		if err != nil {
+			return nil, err
+		}
+		switch s.kind {
+		case kindString:
+			n.op, n.v = opStrVar, s.v
+		case kindBoolean:
+			n.op, n.v = opBoolean, s.v
+		case kindGrouping:
+			n.op, n.set = opGrouping, s.grouping
+		case kindRoutine:
+			n.op, n.routine = opCall, s.routine
+			p.calls = append(p.calls, n)
+		default:
+			return nil, p.errorf(t.line, "%s is not a command", t.text)
+		}
+	}
+	return n, nil
+}
+
+//
+// stringArg reads the string literal or string variable an insertion or
+// replacement takes.
+//
+func (p *parser) stringArg(n *node, literal, variable op) (*node, error) {
+	t, err := p.peek()
+	if err != nil {
+		return nil, err
+	}
+	if t.kind == tokString {
+		p.next()
+		n.op, n.str = literal, t.str
+		return n, nil
+	}
+	n.op = variable
+	return n, p.variable(n, kindString)
+}
+
+//
+// variable reads the name of a variable of kind k into n.v.
+//
+func (p *parser) variable(n *node, k kind) error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	s, err := p.lookup(t)
+	if err != nil {
+		return err
+	}
+	if s.kind != k {
+		return p.errorf(t.line, "%s has the wrong type", t.text)
+	}
+	n.v = s.v
+	return nil
+}
+
+//
+// integerCommand reads an integer assignment or test after a $.
+//
+func (p *parser) integerCommand(n *node) (*node, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOp(t, "(") {
+		n.op = opIntCompare
+		if n.left, err = p.expr(); err != nil {
+			return nil, err
+		}
+		if n.cmp, err = p.relation(); err != nil {
+			return nil, err
+		}
+		if n.expr, err = p.expr(); err != nil {
+			return nil, err
+		}
+		return n, p.expect(")")
+	}
+	s, err := p.lookup(t)
+	if err != nil {
+		return nil, err
+	}
+	if s.kind != kindInteger {
+		return nil, p.errorf(t.line, "%s is not an integer", t.text)
+	}
+	n.v = s.v
+	o, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case p.isOp(o, "=", "+=", "-=", "*=", "/="):
+		n.op, n.cmp = opIntAssign, o.text
+	case p.isOp(o, "==", "!=", "<", "<=", ">", ">="):
+		n.op, n.cmp = opIntCompare, o.text
+		n.left = &expr{op: "var", v: s.v}
+	default:
+		return nil, p.errorf(o.line, "expected an integer operator, found %v", o)
+	}
+	n.expr, err = p.expr()
+	return n, err
+}
+
+func (p *parser) relation() (string, error) {
+	t, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if !p.isOp(t, "==", "!=", "<", "<=", ">", ">=") {
+		return "", p.errorf(t.line, "expected a comparison, found %v", t)
+	}
+	return t.text, nil
+}
+
+//
+// among reads the strings and commands of an among, linking it to the
+// substring before it.
+//
+func (p *parser) among(n *node) (*node, error) {
+	a := &among{id: p.prog.amongs}
+	p.prog.amongs++
+	n.op, n.among = opAmong, a
+	if p.substring != nil {
+		a.linked = true
+		p.substring.among = a
+		p.substring = nil
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	pending := 0
+	seen := make(map[string]bool)
+	for {
+		t, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case t.kind == tokString:
+			if seen[string(t.str)] {
+				return nil, p.errorf(t.line, "%v appears twice in among", t)
+			}
+			seen[string(t.str)] = true
+			a.strings = append(a.strings, amongString{s: t.str, group: len(a.commands)})
+			pending++
+		case t.kind == tokName && !keywords[t.text]:
+			s, err := p.lookup(t)
+			if err != nil {
+				return nil, err
+			}
+			if s.kind != kindRoutine || pending == 0 {
+				return nil, p.errorf(t.line, "unexpected %v in among", t)
+			}
+			r := &node{op: opCall, routine: s.routine, backward: p.backward, line: t.line}
+			p.calls = append(p.calls, r)
+			a.strings[len(a.strings)-1].routine = s.routine
+		case p.isOp(t, "("):
+			c, err := p.operator(t, &node{backward: p.backward, line: t.line})
+			if err != nil {
+				return nil, err
+			}
+			if len(a.strings) == 0 && a.prelude == nil && len(a.commands) == 0 {
+				a.prelude = c
+				continue
+			}
+			if pending == 0 {
+				return nil, p.errorf(t.line, "among command without strings")
+			}
+			a.commands = append(a.commands, c)
+			pending = 0
+		case p.isOp(t, ")"):
+			if pending > 0 {
+				a.commands = append(a.commands, nil)
+			}
+			if len(a.strings) == 0 {
+				return nil, p.errorf(t.line, "among without strings")
+			}
+			sort.SliceStable(a.strings, func(i, j int) bool {
+				return len(a.strings[i].s) > len(a.strings[j].s)
+			})
+			return n, nil
+		default:
+			return nil, p.errorf(t.line, "unexpected %v in among", t)
+		}
+	}
+}
+
+//
+// expr reads an integer expression.
+//
+func (p *parser) expr() (*expr, error) {
+	e, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if !p.isOp(t, "+", "-") {
+			return e, nil
+		}
+		p.next()
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		e = &expr{op: t.text, left: e, right: right}
+	}
+}
+
+func (p *parser) term() (*expr, error) {
+	e, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if !p.isOp(t, "*", "/") {
+			return e, nil
+		}
+		p.next()
+		right, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		e = &expr{op: t.text, left: e, right: right}
+	}
+}
+
+func (p *parser) factor() (*expr, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	switch t.kind {
+	case tokNumber:
+		return &expr{op: "num", n: t.num}, nil
+	case tokOp:
+		switch t.text {
+		case "-":
+			e, err := p.factor()
+			if err != nil {
+				return nil, err
+			}
+			return &expr{op: "neg", left: e}, nil
+		case "(":
+			e, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			return e, p.expect(")")
+		}
+	case tokName:
+		switch t.text {
+		case "cursor", "limit", "size", "len", "maxint", "minint":
+			return &expr{op: t.text}, nil
+		case "sizeof", "lenof":
+			n := &node{}
+			if err := p.variable(n, kindString); err != nil {
+				return nil, err
+			}
+			return &expr{op: "sizeof", v: n.v}, nil
+		}
+		s, err := p.lookup(t)
+		if err != nil {
+			return nil, err
+		}
+		if s.kind != kindInteger {
+			return nil, p.errorf(t.line, "%s is not an integer", t.text)
+		}
+		return &expr{op: "var", v: s.v}, nil
+	}
+	return nil, p.errorf(t.line, "expected an integer expression, found %v", t)
+}
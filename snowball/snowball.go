@@ -0,0 +1,138 @@
+//
+// Package snowball runs stemmers written in Snowball, the string processing
+// language of https://snowballstem.org/, without compiling them to Go first.
+//
+// A Program is the parsed source of a Snowball stemmer; it stems by calling
+// one of its externals, usually stem, on the word:
+//
+//    p, err := snowball.Load("english.sbl")
+//    ...
+//    stem := p.StemString("generously")
+//
+// The interpreter supports the whole language but for string commands ($s C)
+// and get. It works on Unicode characters, so sizes, lengths and hops count
+// characters. testdata has the definitions of the Porter and English
+// stemmers, which give the stems of Stem and StemPorter2.
+//
+package snowball
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//
+// Error reports a problem in a Snowball source along with the line where it
+// was found, or a problem running a program, such as a slice outside the
+// word, with the line of the command.
+//
+type Error struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+}
+
+//
+// Program is a parsed Snowball program. It is safe for concurrent use.
+//
+type Program struct {
+	name      string
+	file      string
+	routines  map[string]*routine
+	externals []string
+	strings   int
+	integers  int
+	booleans  int
+	amongs    int
+}
+
+//
+// Load reads and parses the Snowball program at path. The program is named
+// after the file, without its extension.
+//
+func Load(path string) (*Program, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(path, f)
+}
+
+//
+// Read is Load for a program read from r. file names it in errors.
+//
+func Read(file string, r io.Reader) (*Program, error) {
+	src, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(file, src)
+}
+
+//
+// Parse parses the Snowball source src. file names it in errors, which are
+// *Error for the problems of the source.
+//
+func Parse(file string, src []byte) (*Program, error) {
+	p, err := parse(file, string(src))
+	if err != nil {
+		return nil, err
+	}
+	p.file = file
+	p.name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	return p, nil
+}
+
+//
+// Name returns the name of the program.
+//
+func (p *Program) Name() string { return p.name }
+
+//
+// Externals returns the names of the externals of the program, sorted.
+//
+func (p *Program) Externals() []string {
+	return append([]string(nil), p.externals...)
+}
+
+//
+// Run calls the external routine on word and returns the word it leaves and
+// whether the routine succeeded.
+//
+func (p *Program) Run(routine, word string) (string, bool, error) {
+	r, ok := p.routines[routine]
+	if !ok || !r.external {
+		return word, false, fmt.Errorf("snowball: %s has no external %s", p.name, routine)
+	}
+	e := newEnv(p, []rune(word))
+	ok, err := e.call(r)
+	return string(e.word), ok, err
+}
+
+//
+// Stem lowercases word, trims the white space around it and calls the
+// external stem on it. It returns the word unchanged if the program has no
+// such external or fails to run.
+//
+func (p *Program) Stem(word []byte) []byte {
+	word = bytes.TrimSpace(bytes.ToLower(word))
+	stem, _, err := p.Run("stem", string(word))
+	if err != nil {
+		return word
+	}
+	return []byte(stem)
+}
+
+func (p *Program) StemString(word string) string {
+	return string(p.Stem([]byte(word)))
+}
@@ -0,0 +1,257 @@
+package snowball
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pigi72333/stemmer"
+)
+
+const features = `
+// Exercises the parts of the language the stemmers in testdata leave out.
+stringescapes {}
+stringdef a" hex 'E4'
+
+strings ( s )
+integers ( n )
+booleans ( b )
+routines ( after_vowel suffix )
+externals ( stem umlaut swap count )
+groupings ( v )
+
+define v 'aeiou{a"}'
+
+backwardmode (
+    define after_vowel as v
+    define suffix as (
+        setlimit tomark 2 for ([substring])
+        among (
+            'ing' 'ed' (delete)
+            's' after_vowel (<- 'z')
+        )
+    )
+)
+
+define stem as backwards suffix
+define umlaut as repeat goto (['ae'] <- '{a"}')
+define swap as ([hop 2] -> s delete tolimit insert s)
+define count as (
+    $n = 0
+    repeat (gopast v $n += 1)
+    $(n * 2 > 3) set b
+    b tolimit attach '!' <+ '{'}'
+)
+`
+
+func TestRun(t *testing.T) {
+	p, err := Parse("features.sbl", []byte(features))
+	if err != nil {
+		t.Fatalf("Parse() returned an error: '%v'", err)
+	}
+	if e := strings.Join(p.Externals(), " "); e != "count stem swap umlaut" {
+		t.Errorf("Program.Externals() return value not what was expected, return: '%s' expected: '%s'", e, "count stem swap umlaut")
+	}
+
+	fixtures := [][2]string{
+		{"stem", "jumped"},
+		{"stem", "sing"},
+		{"stem", "toes"},
+		{"stem", "cats"},
+		{"umlaut", "maedchen"},
+		{"swap", "abcde"},
+		{"count", "banana"},
+		{"count", "cat"},
+	}
+
+	results := []string{
+		"jump",
+		"sing",
+		"toez",
+		"cats",
+		"mädchen",
+		"cdeab",
+		"banana'!",
+		"cat",
+	}
+
+	for k, value := range fixtures {
+		if result, _, err := p.Run(value[0], value[1]); result != results[k] || err != nil {
+			t.Errorf("Program.Run() return value not what was expected, pass: '%s %s' return: '%s' '%v' expected: '%s'", value[0], value[1], result, err, results[k])
+		}
+	}
+
+	if _, ok, _ := p.Run("count", "cat"); ok {
+		t.Errorf("Program.Run() succeeded, pass: 'count cat'")
+	}
+	if _, _, err := p.Run("suffix", "cat"); err == nil {
+		t.Errorf("Program.Run() did not return an error for a routine that is not external")
+	}
+}
+
+func TestRunError(t *testing.T) {
+	p, err := Parse("slice.sbl", []byte("externals ( stem )\ndefine stem as (\n  next ] next [ delete\n)\n"))
+	if err != nil {
+		t.Fatalf("Parse() returned an error: '%v'", err)
+	}
+	if _, _, err := p.Run("stem", "word"); err == nil || err.(*Error).Line != 3 {
+		t.Errorf("Program.Run() error not what was expected, return: '%v'", err)
+	}
+	if result := p.StemString("Word"); result != "word" {
+		t.Errorf("Program.StemString() return value not what was expected, return: '%s' expected: '%s'", result, "word")
+	}
+}
+
+func TestParseError(t *testing.T) {
+	fixtures := []string{
+		"externals ( stem )\n",
+		"routines ( r )\nexternals ( stem )\ndefine stem as r\n",
+		"externals ( stem )\ndefine stem as (\n  undeclared\n)\n",
+		"externals ( stem stem )\n",
+		"externals ( stem )\ndefine stem as 'abc\n",
+		"externals ( stem )\ndefine stem as (\n  among ( 'a' 'a' )\n)\n",
+		"externals ( stem )\ndefine stem as (\n  substring\n)\n",
+		"externals ( stem )\nroutines ( r )\nbackwardmode ( define r as 'a' )\ndefine stem as r\n",
+		"stringescapes {}\nexternals ( stem )\ndefine stem as '{x}'\n",
+		"externals ( stem )\ndefine stem as ( $n = 1 )\n",
+		"externals ( stem )\ndefine stem as ( setlimit next next )\n",
+		"/* unterminated\n",
+	}
+
+	lines := []int{1, 1, 3, 1, 2, 3, 3, 4, 3, 2, 2, 1}
+
+	for k, value := range fixtures {
+		_, err := Parse("bad.sbl", []byte(value))
+		if e, ok := err.(*Error); !ok || e.Line != lines[k] {
+			t.Errorf("Parse() error not what was expected, pass: '%s' return: '%v' expected line: '%d'", value, err, lines[k])
+		}
+	}
+}
+
+//
+// testdata/porter.sbl is the Snowball definition of the Porter stemmer. Stem
+// departs from it on a few words, testdata/porter_departures.sbl does not.
+//
+func TestPorter(t *testing.T) {
+	official, err := Load("testdata/porter.sbl")
+	if err != nil {
+		t.Fatalf("Load() returned an error: '%v'", err)
+	}
+	departures, err := Load("testdata/porter_departures.sbl")
+	if err != nil {
+		t.Fatalf("Load() returned an error: '%v'", err)
+	}
+	if name := official.Name(); name != "porter" {
+		t.Errorf("Program.Name() return value not what was expected, return: '%s' expected: '%s'", name, "porter")
+	}
+
+	differences := map[string]string{
+		"apology":     "apologi",
+		"as":          "a",
+		"assemblies":  "assembli",
+		"assembly":    "assembli",
+		"ay":          "ai",
+		"corruptibly": "corruptibli",
+		"dissembly":   "dissembli",
+		"dumbly":      "dumbli",
+		"es":          "e",
+		"ey":          "ei",
+		"forcibly":    "forcibli",
+		"horribly":    "horribli",
+		"humbly":      "humbli",
+		"ignobly":     "ignobli",
+		"infallibly":  "infallibli",
+		"is":          "i",
+		"nimbly":      "nimbli",
+		"ns":          "n",
+		"possibly":    "possibli",
+		"rs":          "r",
+		"s":           "",
+		"sensibly":    "sensibli",
+		"terribly":    "terribli",
+		"ts":          "t",
+		"us":          "u",
+		"uy":          "ui",
+		"visibly":     "visibli",
+	}
+
+	v, err := os.Open("../voc.txt")
+	if err != nil {
+		panic(err)
+	}
+	defer v.Close()
+	vocScanner := bufio.NewScanner(v)
+
+	for vocScanner.Scan() {
+		word := vocScanner.Text()
+		expected := stemmer.StemString(word)
+		if result := departures.StemString(word); result != expected {
+			t.Errorf("porter_departures.sbl return value not what was expected, pass: '%s' return: '%s' expected: '%s'", word, result, expected)
+		}
+		if d, ok := differences[word]; ok {
+			expected = d
+		}
+		if result := official.StemString(word); result != expected {
+			t.Errorf("porter.sbl return value not what was expected, pass: '%s' return: '%s' expected: '%s'", word, result, expected)
+		}
+	}
+}
+
+func TestEnglish(t *testing.T) {
+	p, err := Load("testdata/english.sbl")
+	if err != nil {
+		t.Fatalf("Load() returned an error: '%v'", err)
+	}
+
+	v, err := os.Open("../testdata/porter2/voc.txt")
+	if err != nil {
+		panic(err)
+	}
+	defer v.Close()
+	vocScanner := bufio.NewScanner(v)
+
+	for vocScanner.Scan() {
+		word := vocScanner.Text()
+		expected := string(stemmer.StemPorter2([]byte(word)))
+		if result := p.StemString(word); result != expected {
+			t.Errorf("english.sbl return value not what was expected, pass: '%s' return: '%s' expected: '%s'", word, result, expected)
+		}
+	}
+}
+
+//
+// testdata/lovins.sbl is the Snowball definition of the Lovins stemmer, which
+// StemLovins follows on every word of voc.txt.
+//
+func TestLovins(t *testing.T) {
+	p, err := Load("testdata/lovins.sbl")
+	if err != nil {
+		t.Fatalf("Load() returned an error: '%v'", err)
+	}
+
+	v, err := os.Open("../voc.txt")
+	if err != nil {
+		panic(err)
+	}
+	defer v.Close()
+	vocScanner := bufio.NewScanner(v)
+
+	for vocScanner.Scan() {
+		word := vocScanner.Text()
+		expected := string(stemmer.StemLovins([]byte(word)))
+		if result := p.StemString(word); result != expected {
+			t.Errorf("lovins.sbl return value not what was expected, pass: '%s' return: '%s' expected: '%s'", word, result, expected)
+		}
+	}
+}
+
+func BenchmarkPorter(b *testing.B) {
+	p, err := Load("testdata/porter.sbl")
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		p.StemString("generalizations")
+	}
+}
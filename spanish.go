@@ -0,0 +1,237 @@
+package stemmer
+
+//
+// The Spanish stemmer:
+//
+//    https://snowballstem.org/algorithms/spanish/stemmer.html
+//
+
+const spanishVowels = "aeiouáéíóúü"
+
+var (
+	spanishPronouns = newSuffixList(
+		"me", "se", "sela", "selo", "selas", "selos", "la", "le", "lo",
+		"las", "les", "los", "nos",
+	)
+	spanishPronounEndings = newSuffixList(
+		"iéndo", "ándo", "ár", "ér", "ír", "ando", "iendo", "ar", "er", "ir", "yendo",
+	)
+	spanishStep1Suffixes = newSuffixList(
+		"anza", "anzas", "ico", "ica", "icos", "icas", "ismo", "ismos", "able",
+		"ables", "ible", "ibles", "ista", "istas", "oso", "osa", "osos", "osas",
+		"amiento", "amientos", "imiento", "imientos",
+		"adora", "ador", "ación", "adoras", "adores", "aciones", "ante", "antes", "ancia", "ancias",
+		"logía", "logías",
+		"ución", "uciones",
+		"encia", "encias",
+		"amente",
+		"mente",
+		"idad", "idades",
+		"iva", "ivo", "ivas", "ivos",
+	)
+	spanishYVerbSuffixes = newSuffixList(
+		"ya", "ye", "yan", "yen", "yeron", "yendo", "yo", "yó", "yas", "yes", "yais", "yamos",
+	)
+	spanishVerbSuffixes = newSuffixList(
+		"en", "es", "éis", "emos",
+		"arían", "arías", "arán", "arás", "aríais", "aría", "aréis", "aríamos",
+		"aremos", "ará", "aré", "erían", "erías", "erán", "erás", "eríais", "ería",
+		"eréis", "eríamos", "eremos", "erá", "eré", "irían", "irías", "irán",
+		"irás", "iríais", "iría", "iréis", "iríamos", "iremos", "irá", "iré", "aba",
+		"ada", "ida", "ía", "ara", "iera", "ad", "ed", "id", "ase", "iese", "aste",
+		"iste", "an", "aban", "ían", "aran", "ieran", "asen", "iesen", "aron",
+		"ieron", "ado", "ido", "ando", "iendo", "ió", "ar", "er", "ir", "as",
+		"abas", "adas", "idas", "ías", "aras", "ieras", "ases", "ieses", "ís",
+		"áis", "abais", "íais", "arais", "ierais", "aseis", "ieseis", "asteis",
+		"isteis", "ados", "idos", "amos", "ábamos", "íamos", "imos", "áramos",
+		"iéramos", "iésemos", "ásemos",
+	)
+	spanishResidualSuffixes = newSuffixList("os", "a", "o", "á", "í", "ó", "e", "é")
+)
+
+func stemSpanish(w []rune) []rune {
+	// A word of one letter is its own stem, accent and all, as in the
+	// vocabulary published with the algorithm, where "ó" stays "ó".
+	if len(w) < 2 {
+		return w
+	}
+
+	rv := romanceRV(w, spanishVowels)
+	r1 := regionAfter(w, 0, spanishVowels)
+	r2 := regionAfter(w, r1, spanishVowels)
+
+	w = spanishPronoun(w, rv)
+
+	// Step 1 removes the standard suffixes, and only if it does not, step 2a
+	// the verb suffixes beginning with y and step 2b the other verb suffixes.
+	var ok bool
+	if w, ok = spanishStandardSuffix(w, r1, r2); !ok {
+		if w, ok = spanishYVerbSuffix(w, rv); !ok {
+			w = spanishVerbSuffix(w, rv)
+		}
+	}
+
+	// Step 3: the residual suffixes in RV, and a u between g and e or é.
+	switch s, start := spanishResidualSuffixes.find(w, 0); s {
+	case "os", "a", "o", "á", "í", "ó":
+		if start >= rv {
+			w = w[:start]
+		}
+	case "e", "é":
+		if start >= rv {
+			w = w[:start]
+			if u := suffixStart(w, "u"); u >= rv && inGroup(w, u-1, "g") {
+				w = w[:u]
+			}
+		}
+	}
+
+	for i, r := range w {
+		switch r {
+		case 'á':
+			w[i] = 'a'
+		case 'é':
+			w[i] = 'e'
+		case 'í':
+			w[i] = 'i'
+		case 'ó':
+			w[i] = 'o'
+		case 'ú':
+			w[i] = 'u'
+		}
+	}
+	return w
+}
+
+//
+// spanishPronoun is step 0: an attached pronoun after a gerund or an
+// infinitive in RV is removed, and the accent the verb then no longer needs
+// with it.
+//
+func spanishPronoun(w []rune, rv int) []rune {
+	s, start := spanishPronouns.find(w, 0)
+	if s == "" {
+		return w
+	}
+	verb, vstart := spanishPronounEndings.find(w[:start], 0)
+	if verb == "" || vstart < rv {
+		return w
+	}
+	switch verb {
+	case "iéndo":
+		return replaceSuffix(w, vstart, "iendo")
+	case "ándo":
+		return replaceSuffix(w, vstart, "ando")
+	case "ár":
+		return replaceSuffix(w, vstart, "ar")
+	case "ér":
+		return replaceSuffix(w, vstart, "er")
+	case "ír":
+		return replaceSuffix(w, vstart, "ir")
+	case "yendo":
+		if !inGroup(w, vstart-1, "u") {
+			return w
+		}
+	}
+	return w[:start]
+}
+
+//
+// spanishStandardSuffix is step 1, and reports whether it removed a suffix.
+//
+func spanishStandardSuffix(w []rune, r1, r2 int) ([]rune, bool) {
+	s, start := spanishStep1Suffixes.find(w, 0)
+	if s == "" {
+		return w, false
+	}
+	switch s {
+	case "amente":
+		if start < r1 {
+			return w, false
+		}
+	default:
+		if start < r2 {
+			return w, false
+		}
+	}
+
+	// removeInR2 removes each of the suffixes in turn from the end of w
+	// while it is in R2.
+	removeInR2 := func(suffixes ...string) {
+		for _, s := range suffixes {
+			start := suffixStart(w, s)
+			if start < r2 {
+				return
+			}
+			w = w[:start]
+		}
+	}
+
+	switch s {
+	case "adora", "ador", "ación", "adoras", "adores", "aciones", "ante", "antes", "ancia", "ancias":
+		w = w[:start]
+		removeInR2("ic")
+	case "logía", "logías":
+		w = replaceSuffix(w, start, "log")
+	case "ución", "uciones":
+		w = replaceSuffix(w, start, "u")
+	case "encia", "encias":
+		w = replaceSuffix(w, start, "ente")
+	case "amente":
+		w = w[:start]
+		switch s, start := newSuffixList("iv", "os", "ic", "ad").find(w, 0); s {
+		case "iv":
+			removeInR2("iv", "at")
+		case "os", "ic", "ad":
+			if start >= r2 {
+				w = w[:start]
+			}
+		}
+	case "mente":
+		w = w[:start]
+		if s, _ := newSuffixList("ante", "able", "ible").find(w, 0); s != "" {
+			removeInR2(s)
+		}
+	case "idad", "idades":
+		w = w[:start]
+		if s, _ := newSuffixList("abil", "ic", "iv").find(w, 0); s != "" {
+			removeInR2(s)
+		}
+	case "iva", "ivo", "ivas", "ivos":
+		w = w[:start]
+		removeInR2("at")
+	default:
+		w = w[:start]
+	}
+	return w, true
+}
+
+//
+// spanishYVerbSuffix is step 2a: the verb suffixes beginning with y in RV,
+// after a u. It reports whether it removed a suffix.
+//
+func spanishYVerbSuffix(w []rune, rv int) ([]rune, bool) {
+	s, start := spanishYVerbSuffixes.find(w, rv)
+	if s == "" || !inGroup(w, start-1, "u") {
+		return w, false
+	}
+	return w[:start], true
+}
+
+//
+// spanishVerbSuffix is step 2b: the other verb suffixes in RV. en, es, éis
+// and emos also take the u of a preceding gu.
+//
+func spanishVerbSuffix(w []rune, rv int) []rune {
+	switch s, start := spanishVerbSuffixes.find(w, rv); s {
+	case "":
+	case "en", "es", "éis", "emos":
+		if inGroup(w, start-1, "u") && inGroup(w, start-2, "g") {
+			start--
+		}
+		w = w[:start]
+	default:
+		w = w[:start]
+	}
+	return w
+}
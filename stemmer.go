@@ -1,6 +1,10 @@
 package stemmer
 
-import "bytes"
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
 
 const (
 	vowel_state = iota
@@ -28,7 +32,8 @@ func measure(word []byte) int {
 
 //
 // A \consonant\ in a word is a letter other than A, E, I, O or U, and other
-// than Y preceded by a consonant.
+// than Y preceded by a consonant. Every byte of a letter that is not ASCII is
+// classified like the letter, see vowelRune.
 //
 func consonant(word []byte, i int) bool {
 	switch word[i] {
@@ -41,6 +46,10 @@ func consonant(word []byte, i int) bool {
 			return (i > 0 && !consonant(word, i-1))
 		}
 	default:
+		if word[i] >= utf8.RuneSelf {
+			r, _ := utf8.DecodeRune(word[letterStart(word, i):])
+			return !vowelRune(r)
+		}
 		return true
 	}
 }
@@ -61,414 +70,135 @@ func containVowel(word []byte) bool {
 	return false
 }
 
-//
-// Step 1a
-//
-//    SSES -> SS                         caresses  ->  caress
-//    IES  -> I                          ponies    ->  poni
-//                                       ties      ->  ti
-//    SS   -> SS                         caress    ->  caress
-//    S    ->                            cats      ->  cat
-//
-func firstA(word []byte) []byte {
-	if bytes.HasSuffix(word, []byte("s")) {
-		if bytes.HasSuffix(word, []byte("sses")) || bytes.HasSuffix(word, []byte("ies")) {
-			return word[:len(word)-2]
-		} else if bytes.HasSuffix(word, []byte("ss")) {
-			return word
-		}
-		return word[:len(word)-1]
-	}
-	return word
-}
-
-//
-// Step 1b
-//
-//    (m>0) EED -> EE                    feed      ->  feed
-//                                       agreed    ->  agree
-//    (*v*) ED  ->                       plastered ->  plaster
-//                                       bled      ->  bled
-//    (*v*) ING ->                       motoring  ->  motor
-//                                       sing      ->  sing
-//
-func firstB(word []byte) []byte {
-	l := len(word)
-
-	if bytes.HasSuffix(word, []byte("ed")) {
-		if bytes.HasSuffix(word, []byte("eed")) {
-			if m := measure(word[:l-3]); m > 0 {
-				return bytes.TrimSuffix(word, []byte("d"))
-			}
-		} else {
-			if containVowel(word[:l-2]) {
-				return firstB2(bytes.TrimSuffix(word, []byte("ed")))
-			}
-		}
-
-	} else if bytes.HasSuffix(word, []byte("ing")) {
-		if containVowel(word[:l-3]) {
-			return firstB2(bytes.TrimSuffix(word, []byte("ing")))
-		}
-	}
-	return word
-}
-
-//
-//  AT -> ATE                       conflat(ed)  ->  conflate
-//  BL -> BLE                       troubl(ed)   ->  trouble
-//  IZ -> IZE                       siz(ed)      ->  size
-//  (*d and not (*L or *S or *Z))
-//     -> single letter
-//                                  hopp(ing)    ->  hop
-//                                  tann(ed)     ->  tan
-//                                  fall(ing)    ->  fall
-//                                  hiss(ing)    ->  hiss
-//                                  fizz(ed)     ->  fizz
-//  (m=1 and *o) -> E               fail(ing)    ->  fail
-//                                  fil(ing)     ->  file
-//
-func firstB2(word []byte) []byte {
-	l := len(word)
-	if bytes.HasSuffix(word, []byte("at")) || bytes.HasSuffix(word, []byte("iz")) || bytes.HasSuffix(word, []byte("bl")) {
-		return append(word, 'e')
-		// (*d and not (*L or *S or *Z)) -> single letter
-	} else if consonant(word, l-1) && word[l-1] == word[l-2] {
-		if !bytes.HasSuffix(word, []byte("l")) && !bytes.HasSuffix(word, []byte("s")) && !bytes.HasSuffix(word, []byte("z")) {
-			return word[:l-1]
-		}
-	} else if m := measure(word); m == 1 {
-		//*o  - the stem ends cvc, where the second c is not W, X or Y (e.g. -WIL, -HOP).
-		//(m=1 and *o) -> E
-		if isCVCSuffix(word) {
-			return append(word, 'e')
-		}
-	}
-	return word
-}
-
 //
 //*o  - the stem ends cvc, where the second c is not W, X or Y (e.g.
 //       -WIL, -HOP)
 //
 func isCVCSuffix(word []byte) bool {
 	size := len(word) - 1
-	if size >= 2 && consonant(word, size-2) && vowel(word, size-1) && consonant(word, size) && word[size] != 'w' && word[size] != 'x' && word[size] != 'y' {
+	if size < 2 {
+		return false
+	}
+	// The last three letters start at c1, v and c2.
+	c2 := letterStart(word, size)
+	if c2 < 2 {
+		return false
+	}
+	v := letterStart(word, c2-1)
+	if v < 1 {
+		return false
+	}
+	c1 := letterStart(word, v-1)
+	if consonant(word, c1) && vowel(word, v) && consonant(word, c2) && word[c2] != 'w' && word[c2] != 'x' && word[c2] != 'y' {
 		return true
 	}
 	return false
 }
 
 //
-// Step 1c
+// The step functions run one step of PorterSteps each.
 //
-//    (*v*) Y -> I                    happy        ->  happi
-//                                    sky          ->  sky
+func firstA(word []byte, t *trace) []byte { return porterRules.steps[0].apply(word, t) }
+func firstB(word []byte, t *trace) []byte { return porterRules.steps[1].apply(word, t) }
+func firstC(word []byte, t *trace) []byte { return porterRules.steps[2].apply(word, t) }
+func second(word []byte, t *trace) []byte { return porterRules.steps[3].apply(word, t) }
+func third(word []byte, t *trace) []byte  { return porterRules.steps[4].apply(word, t) }
+func four(word []byte, t *trace) []byte   { return porterRules.steps[5].apply(word, t) }
+func fiveA(word []byte, t *trace) []byte  { return porterRules.steps[6].apply(word, t) }
+func fiveB(word []byte, t *trace) []byte  { return porterRules.steps[7].apply(word, t) }
+
 //
-func firstC(word []byte) []byte {
-	l := len(word)
-	if bytes.HasSuffix(word, []byte("y")) && containVowel(word[:l-1]) {
-		word[l-1] = byte('i')
-		return word
-	}
-	return word
+// Stem returns the stem of word in a newly allocated slice. word is never
+// modified.
+//
+func Stem(word []byte) []byte {
+	return AppendStem(make([]byte, 0, len(word)), word)
 }
 
 //
-// Step 2
+// AppendStem appends the stem of word to dst and returns the extended buffer.
+// word is never modified, and dst and word must not overlap.
 //
-//    (m>0) ATIONAL ->  ATE           relational     ->  relate
-//    (m>0) TIONAL  ->  TION          conditional    ->  condition
-//                                    rational       ->  rational
-//    (m>0) ENCI    ->  ENCE          valenci        ->  valence
-//    (m>0) ANCI    ->  ANCE          hesitanci      ->  hesitance
-//    (m>0) IZER    ->  IZE           digitizer      ->  digitize
-//    (m>0) ABLI    ->  ABLE          conformabli    ->  conformable
-//    (m>0) ALLI    ->  AL            radicalli      ->  radical
-//    (m>0) ENTLI   ->  ENT           differentli    ->  different
-//    (m>0) ELI     ->  E             vileli        - >  vile
-//    (m>0) OUSLI   ->  OUS           analogousli    ->  analogous
-//    (m>0) IZATION ->  IZE           vietnamization ->  vietnamize
-//    (m>0) ATION   ->  ATE           predication    ->  predicate
-//    (m>0) ATOR    ->  ATE           operator       ->  operate
-//    (m>0) ALISM   ->  AL            feudalism      ->  feudal
-//    (m>0) IVENESS ->  IVE           decisiveness   ->  decisive
-//    (m>0) FULNESS ->  FUL           hopefulness    ->  hopeful
-//    (m>0) OUSNESS ->  OUS           callousness    ->  callous
-//    (m>0) ALITI   ->  AL            formaliti      ->  formal
-//    (m>0) IVITI   ->  IVE           sensitiviti    ->  sensitive
-//    (m>0) BILITI  ->  BLE           sensibiliti    ->  sensible
+// The stem is never longer than word, so if dst has room for len(word) more
+// bytes and word is ASCII, AppendStem does not allocate.
 //
-func second(word []byte) []byte {
-	l := len(word)
-	if bytes.HasSuffix(word, []byte("tional")) {
-		if bytes.HasSuffix(word, []byte("ational")) {
-			if m := measure(word[:l-7]); m > 0 {
-				return append(bytes.TrimSuffix(word, []byte("ional")), 'e')
-			}
-		} else if m := measure(word[:l-2]); m > 0 {
-			return bytes.TrimSuffix(word, []byte("al"))
-		}
-	} else if bytes.HasSuffix(word, []byte("nci")) {
-		if bytes.HasSuffix(word, []byte("enci")) || bytes.HasSuffix(word, []byte("anci")) {
-			if m := measure(word[:l-4]); m > 0 {
-				return append(word[:len(word)-1], 'e')
-			}
-		}
-	} else if bytes.HasSuffix(word, []byte("li")) {
-		if bytes.HasSuffix(word, []byte("abli")) {
-			if m := measure(word[:l-4]); m > 0 {
-				return append(word[:len(word)-1], 'e')
-			}
-		} else if bytes.HasSuffix(word, []byte("bli")) {
-			if m := measure(word[:l-3]); m > 0 {
-				return append(word[:l-1], 'e')
-			}
-		} else if bytes.HasSuffix(word, []byte("alli")) {
-			if m := measure(word[:l-4]); m > 0 {
-				return word[:l-2]
-			}
-		} else if bytes.HasSuffix(word, []byte("entli")) || bytes.HasSuffix(word, []byte("ousli")) {
-			if m := measure(word[:l-5]); m > 0 {
-				return word[:l-2]
-			}
-		} else if bytes.HasSuffix(word, []byte("eli")) {
-			if m := measure(word[:l-3]); m > 0 {
-				return word[:l-2]
-			}
-		}
-	} else if bytes.HasSuffix(word, []byte("ti")) {
-		if bytes.HasSuffix(word, []byte("iviti")) {
-			if m := measure(word[:l-5]); m > 0 {
-				return append(word[:l-3], 'e')
-			}
-		} else if bytes.HasSuffix(word, []byte("aliti")) {
-			if m := measure(word[:l-5]); m > 0 {
-				return word[:l-3]
-			}
-		} else if bytes.HasSuffix(word, []byte("biliti")) {
-			if m := measure(word[:l-6]); m > 0 {
-				return append(word[:l-5], []byte("le")...)
-			}
-		}
-	} else if bytes.HasSuffix(word, []byte("ation")) {
-		if bytes.HasSuffix(word, []byte("ization")) {
-			if m := measure(word[:l-7]); m > 0 {
-				return append(word[:len(word)-5], 'e')
-			}
-		} else if m := measure(word[:l-5]); m > 0 {
-			return append(word[:l-3], 'e')
-		}
-	} else if bytes.HasSuffix(word, []byte("ness")) {
-		if bytes.HasSuffix(word, []byte("iveness")) || bytes.HasSuffix(word, []byte("fulness")) || bytes.HasSuffix(word, []byte("ousness")) {
-			if m := measure(word[:l-7]); m > 0 {
-				return word[:l-4]
-			}
-		}
-	} else if bytes.HasSuffix(word, []byte("izer")) {
-		if m := measure(word[:l-4]); m > 0 {
-			return word[:l-1]
-		}
-	} else if bytes.HasSuffix(word, []byte("alism")) {
-		if m := measure(word[:l-5]); m > 0 {
-			return word[:l-3]
-		}
-	} else if bytes.HasSuffix(word, []byte("ator")) {
-		if m := measure(word[:l-4]); m > 0 {
-			return append(word[:len(word)-2], 'e')
-		}
-	} else if bytes.HasSuffix(word, []byte("logi")) {
-		if m := measure(word[:l-4]); m > 0 {
-			return append(word[:l-1])
-		}
-	}
-	return word
+func AppendStem(dst, word []byte) []byte {
+	n := len(dst)
+	dst = appendLower(dst, bytes.TrimSpace(word))
+	return append(dst[:n], porterRun(dst[n:])...)
 }
 
 //
-// Step 3
+// StemString returns the stem of word. When the stem is word itself, word is
+// returned as is and nothing is allocated.
 //
-//    (m>0) ICATE ->  IC              triplicate     ->  triplic
-//    (m>0) ATIVE ->                  formative      ->  form
-//    (m>0) ALIZE ->  AL              formalize      ->  formal
-//    (m>0) ICITI ->  IC              electriciti    ->  electric
-//    (m>0) ICAL  ->  IC              electrical     ->  electric
-//    (m>0) FUL   ->                  hopeful        ->  hope
-//    (m>0) NESS  ->                  goodness       ->  good
+func StemString(word string) string {
+	var buf [64]byte
+	stem, _ := stemString(buf[:0], word)
+	return stem
+}
+
 //
-func third(word []byte) []byte {
-	l := len(word)
-	if bytes.HasSuffix(word, []byte("e")) {
-		if bytes.HasSuffix(word, []byte("icate")) {
-			if measure(word[:l-5]) > 0 {
-				return word[:l-3]
-			}
-		} else if bytes.HasSuffix(word, []byte("ative")) {
-			if measure(word[:l-5]) > 0 {
-				return word[:l-5]
-			}
-		} else if bytes.HasSuffix(word, []byte("alize")) {
-			if measure(word[:l-5]) > 0 {
-				return word[:l-3]
-			}
-		}
-	} else if bytes.HasSuffix(word, []byte("iciti")) {
-		if measure(word[:l-5]) > 0 {
-			return word[:l-3]
-		}
-	} else if bytes.HasSuffix(word, []byte("ical")) {
-		if measure(word[:l-4]) > 0 {
-			return word[:l-2]
-		}
-	} else if bytes.HasSuffix(word, []byte("ful")) {
-		if measure(word[:l-3]) > 0 {
-			return word[:l-3]
-		}
-	} else if bytes.HasSuffix(word, []byte("ness")) {
-		if measure(word[:l-4]) > 0 {
-			return word[:l-4]
-		}
+// StemWords returns the stems of words, in the same order. A single buffer is
+// shared by all the words, and like StemString a word that is its own stem is
+// returned without a copy.
+//
+func StemWords(words []string) []string {
+	var buf [64]byte
+	b := buf[:0]
+	stems := make([]string, len(words))
+	for i, word := range words {
+		stems[i], b = stemString(b[:0], word)
 	}
-	return word
+	return stems
 }
 
 //
-// Step 4
-//
-//    (m>1) AL    ->                  revival        ->  reviv
-//    (m>1) ANCE  ->                  allowance      ->  allow
-//    (m>1) ENCE  ->                  inference      ->  infer
-//    (m>1) ER    ->                  airliner       ->  airlin
-//    (m>1) IC    ->                  gyroscopic     ->  gyroscop
-//    (m>1) ABLE  ->                  adjustable     ->  adjust
-//    (m>1) IBLE  ->                  defensible     ->  defens
-//    (m>1) ANT   ->                  irritant       ->  irrit
-//    (m>1) EMENT ->                  replacement    ->  replac
-//    (m>1) MENT  ->                  adjustment     ->  adjust
-//    (m>1) ENT   ->                  dependent      ->  depend
-//    (m>1 and (*S or *T)) ION ->     adoption       ->  adopt
-//    (m>1) OU    ->                  homologou      ->  homolog
-//    (m>1) ISM   ->                  communism      ->  commun
-//    (m>1) ATE   ->                  activate       ->  activ
-//    (m>1) ITI   ->                  angulariti     ->  angular
-//    (m>1) OUS   ->                  homologous     ->  homolog
-//    (m>1) IVE   ->                  effective      ->  effect
-//    (m>1) IZE   ->                  bowdlerize     ->  bowdler
+// stemString is RuleStemmer.stemString for porterRun.
 //
-func four(word []byte) []byte {
-	l := len(word)
-	if bytes.HasSuffix(word, []byte("al")) || bytes.HasSuffix(word, []byte("er")) || bytes.HasSuffix(word, []byte("ic")) {
-		if m := measure(word[:l-2]); m > 1 {
-			return word[:l-2]
-		}
-	} else if bytes.HasSuffix(word, []byte("nce")) {
-		//if word[l-4] == 'a' || word[l-4] == 'e' {
-		if bytes.HasSuffix(word, []byte("ance")) || bytes.HasSuffix(word, []byte("ence")) {
-			if m := measure(word[:l-4]); m > 1 {
-				return word[:l-4]
-			}
-		}
-	} else if bytes.HasSuffix(word, []byte("ble")) {
-		if word[l-4] == 'a' || word[l-4] == 'i' {
-			//if bytes.HasSuffix(word, []byte("able")) || bytes.HasSuffix(word, []byte("ible")) {
-			if m := measure(word[:l-4]); m > 1 {
-				return word[:l-4]
-			}
-		}
-	} else if bytes.HasSuffix(word, []byte("ent")) {
-		if bytes.HasSuffix(word, []byte("ement")) {
-			if m := measure(word[:l-5]); m > 1 {
-				return word[:l-5]
-			}
-		} else if bytes.HasSuffix(word, []byte("ment")) {
-			if m := measure(word[:l-4]); m > 1 {
-				return word[:l-4]
-			}
-		} else if m := measure(word[:l-3]); m > 1 {
-			return word[:l-3]
-		}
-	} else if bytes.HasSuffix(word, []byte("ant")) {
-		if m := measure(word[:l-3]); m > 1 {
-			return word[:l-3]
-		}
-	} else if bytes.HasSuffix(word, []byte("e")) {
-		if bytes.HasSuffix(word, []byte("ate")) || bytes.HasSuffix(word, []byte("ive")) || bytes.HasSuffix(word, []byte("ize")) {
-			if m := measure(word[:l-3]); m > 1 {
-				return word[:l-3]
-			}
-		}
-	} else if bytes.HasSuffix(word, []byte("ism")) {
-		if m := measure(word[:l-3]); m > 1 {
-			return word[:l-3]
-		}
-	} else if bytes.HasSuffix(word, []byte("ous")) || bytes.HasSuffix(word, []byte("iti")) {
-		if m := measure(word[:l-3]); m > 1 {
-			return word[:l-3]
-		}
-	} else if bytes.HasSuffix(word, []byte("ou")) {
-		if m := measure(word[:l-2]); m > 1 {
-			return word[:l-2]
-		}
-	} else if bytes.HasSuffix(word, []byte("ion")) {
-		// *S  - the stem ends with S (and similarly for the other letters). (m>1 and (*S or *T)) ION ->
-		l := len(word)
-		if measure(word[:l-3]) > 1 {
-			if l > 4 && (word[l-4] == 's' || word[l-4] == 't') {
-				return word[:l-3]
-			}
-		}
+func stemString(buf []byte, word string) (string, []byte) {
+	buf = appendLowerString(buf, strings.TrimSpace(word))
+	buf = porterRun(buf)
+	if string(buf) == word {
+		return word, buf
 	}
-	return word
+	return string(buf), buf
 }
 
 //
-// Step 5a
-//
-//    (m>1) E     ->                  probate        ->  probat
-//                                    rate           ->  rate
-//    (m=1 and not *o) E ->           cease          ->  ceas
+// appendLower appends word to dst with upper case letters replaced by their
+// lower case. Only non-ASCII words go through appendNormalized and allocate.
 //
-func fiveA(word []byte) []byte {
-	l := len(word)
-	if bytes.HasSuffix(word, []byte("e")) {
-		if m := measure(word[:l-1]); m > 1 {
-			return word[:l-1]
-		} else if m := measure(word[:l-1]); m == 1 {
-			if !isCVCSuffix(word[:l-1]) {
-				return word[:l-1]
-			}
+func appendLower(dst, word []byte) []byte {
+	for _, c := range word {
+		if c >= utf8.RuneSelf {
+			return appendNormalized(dst, string(word))
+		}
+	}
+	for _, c := range word {
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
 		}
+		dst = append(dst, c)
 	}
-	return word
+	return dst
 }
 
 //
-// Step 5b
+// appendLowerString is appendLower for strings.
 //
-//    (m > 1 and *d and *L) -> single letter
-//                                    controll       ->  control
-//                                    roll           ->  roll
-//
-func fiveB(word []byte) []byte {
-	l := len(word)
-	if measure(word) > 1 && consonant(word, l-1) && consonant(word, l-2) && word[l-1] == word[l-2] && word[l-1] == 'l' {
-		return word[:l-1]
+func appendLowerString(dst []byte, word string) []byte {
+	for i := 0; i < len(word); i++ {
+		if word[i] >= utf8.RuneSelf {
+			return appendNormalized(dst, word)
+		}
 	}
-	return word
-}
-
-func Stem(word []byte) []byte {
-	word = bytes.TrimSpace(bytes.ToLower(word))
-	if len(word) < 3 {
-		return word
+	for i := 0; i < len(word); i++ {
+		c := word[i]
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		dst = append(dst, c)
 	}
-	word = firstA(word)
-	word = firstB(word)
-	word = firstC(word)
-	word = second(word)
-	word = third(word)
-	word = four(word)
-	word = fiveA(word)
-	word = fiveB(word)
-	return word
+	return dst
 }
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -27,7 +28,7 @@ func TestFirstA(t *testing.T) {
 	}
 
 	for k, value := range fixtures {
-		if result := firstA(value); !bytes.Equal(result, stemmed[k]) {
+		if result := firstA(value, nil); !bytes.Equal(result, stemmed[k]) {
 			t.Errorf("firstA() return value not what was expected, pass: '%v' return: '%v' expected: '%v'", value, result, stemmed[k])
 		}
 	}
@@ -53,7 +54,7 @@ func TestFirstB(t *testing.T) {
 	}
 
 	for k, value := range fixtures {
-		if result := firstB([]byte(value)); !bytes.Equal(result, []byte(stemmed[k])) {
+		if result := firstB([]byte(value), nil); !bytes.Equal(result, []byte(stemmed[k])) {
 			t.Errorf("firstB() return value not what was expected, pass: '%v' return: '%v' expected: '%v'", value, result, stemmed[k])
 		}
 	}
@@ -71,7 +72,7 @@ func TestFirstC(t *testing.T) {
 	}
 
 	for k, value := range fixtures {
-		if result := firstC([]byte(value)); !bytes.Equal(result, []byte(stemmed[k])) {
+		if result := firstC([]byte(value), nil); !bytes.Equal(result, []byte(stemmed[k])) {
 			t.Errorf("firstC() return value not what was expected, pass: '%v' return: '%v' expected: '%v'", value, result, stemmed[k])
 		}
 	}
@@ -127,7 +128,7 @@ func TestSecond(t *testing.T) {
 	}
 
 	for k, value := range fixtures {
-		if result := second([]byte(value)); !bytes.Equal(result, []byte(stemmed[k])) {
+		if result := second([]byte(value), nil); !bytes.Equal(result, []byte(stemmed[k])) {
 			t.Errorf("second() return value not what was expected, pass: '%v' return: '%v' expected: '%v'", value, result, stemmed[k])
 		}
 	}
@@ -156,7 +157,7 @@ func TestThird(t *testing.T) {
 	}
 
 	for k, value := range fixtures {
-		if result := third([]byte(value)); !bytes.Equal(result, []byte(stemmed[k])) {
+		if result := third([]byte(value), nil); !bytes.Equal(result, []byte(stemmed[k])) {
 			t.Errorf("third() return value not what was expected, pass: '%v' return: '%v' expected: '%v'", value, result, stemmed[k])
 		}
 	}
@@ -208,7 +209,7 @@ func TestFour(t *testing.T) {
 	}
 
 	for k, value := range fixtures {
-		if result := four([]byte(value)); !bytes.Equal(result, []byte(stemmed[k])) {
+		if result := four([]byte(value), nil); !bytes.Equal(result, []byte(stemmed[k])) {
 			t.Errorf("four() return value not what was expected, pass: '%v' return: '%v' expected: '%v'", value, result, stemmed[k])
 		}
 	}
@@ -228,7 +229,7 @@ func TestFiveA(t *testing.T) {
 	}
 
 	for k, value := range fixtures {
-		if result := fiveA(value); !bytes.Equal(result, stemmed[k]) {
+		if result := fiveA(value, nil); !bytes.Equal(result, stemmed[k]) {
 			t.Errorf("fiveA() return value not what was expected, pass: '%v' return: '%v' expected: '%v'", value, result, stemmed[k])
 		}
 	}
@@ -246,7 +247,7 @@ func TestFiveB(t *testing.T) {
 	}
 
 	for k, value := range fixtures {
-		if result := fiveB(value); !bytes.Equal(result, stemmed[k]) {
+		if result := fiveB(value, nil); !bytes.Equal(result, stemmed[k]) {
 			t.Errorf("fiveB() return value not what was expected, pass: '%v' return: '%v' expected: '%v'", value, result, stemmed[k])
 		}
 	}
@@ -370,18 +371,85 @@ func TestStem(t *testing.T) {
 	}
 }
 
-func TestisCVCSuffix(t *testing.T) {
+func TestAppendStem(t *testing.T) {
+	word := []byte("Happy")
+	dst := []byte("stem: ")
+
+	if result := AppendStem(dst, word); !bytes.Equal(result, []byte("stem: happi")) {
+		t.Errorf("AppendStem() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", word, result, "stem: happi")
+	}
+	if !bytes.Equal(word, []byte("Happy")) {
+		t.Errorf("AppendStem() modified its input, pass: '%s' now: '%s'", "Happy", word)
+	}
+}
+
+func TestAppendStemAllocs(t *testing.T) {
+	dst := make([]byte, 0, 64)
+	for _, word := range []string{"troubles", "conflated", "happy", "vietnamization", "as"} {
+		w := []byte(word)
+		if allocs := testing.AllocsPerRun(100, func() { AppendStem(dst[:0], w) }); allocs != 0 {
+			t.Errorf("AppendStem() allocated, pass: '%s' allocs: '%v' expected: '%v'", word, allocs, 0)
+		}
+	}
+}
+
+func TestStemString(t *testing.T) {
+	fixtures := []string{
+		"caresses",
+		"Relational",
+		" hopping ",
+		"tree",
+		"as",
+	}
+
+	stemmed := []string{
+		"caress",
+		"relat",
+		"hop",
+		"tree",
+		"as",
+	}
+
+	for k, value := range fixtures {
+		if result := StemString(value); result != stemmed[k] {
+			t.Errorf("StemString() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, stemmed[k])
+		}
+	}
+
+	if result := StemWords(fixtures); !reflect.DeepEqual(result, stemmed) {
+		t.Errorf("StemWords() return value not what was expected, pass: '%q' return: '%q' expected: '%q'", fixtures, result, stemmed)
+	}
+}
+
+func TestStemStringAllocs(t *testing.T) {
+	word := "tree"
+	if allocs := testing.AllocsPerRun(100, func() { StemString(word) }); allocs != 0 {
+		t.Errorf("StemString() allocated, pass: '%s' allocs: '%v' expected: '%v'", word, allocs, 0)
+	}
+
+	word = "troubles"
+	if allocs := testing.AllocsPerRun(100, func() { StemString(word) }); allocs != 1 {
+		t.Errorf("StemString() allocated, pass: '%s' allocs: '%v' expected: '%v'", word, allocs, 1)
+	}
+}
+
+func TestIsCVCSuffix(t *testing.T) {
 	word := []byte("tyt")
 	if condition := isCVCSuffix(word); condition == false {
 		t.Errorf("vowel() return value not what was expected, pass: '%s' return: '%v' expected: '%v'", word, false, true)
 	}
 
 	word = []byte("wil")
-	if condition := isCVCSuffix(word); condition == true {
-		t.Errorf("vowel() return value not what was expected, pass: '%s' return: '%v' expected: '%v'", word, true, false)
+	if condition := isCVCSuffix(word); condition == false {
+		t.Errorf("vowel() return value not what was expected, pass: '%s' return: '%v' expected: '%v'", word, false, true)
 	}
 
 	word = []byte("hop")
+	if condition := isCVCSuffix(word); condition == false {
+		t.Errorf("vowel() return value not what was expected, pass: '%s' return: '%v' expected: '%v'", word, false, true)
+	}
+
+	word = []byte("box")
 	if condition := isCVCSuffix(word); condition == true {
 		t.Errorf("vowel() return value not what was expected, pass: '%s' return: '%v' expected: '%v'", word, true, false)
 	}
@@ -421,58 +489,78 @@ func BenchmarkStem(b *testing.B) {
 	}
 }
 
+func BenchmarkAppendStem(b *testing.B) {
+	word := []byte("troubles")
+	dst := make([]byte, 0, 64)
+	if allocs := testing.AllocsPerRun(100, func() { AppendStem(dst[:0], word) }); allocs != 0 {
+		b.Fatalf("AppendStem() allocated, pass: '%s' allocs: '%v' expected: '%v'", word, allocs, 0)
+	}
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		dst = AppendStem(dst[:0], word)
+	}
+}
+
+func BenchmarkStemString(b *testing.B) {
+	word := "troubles"
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		StemString(word)
+	}
+}
+
 func BenchmarkFirstA(b *testing.B) {
 	word := []byte("caresses")
 	for n := 0; n < b.N; n++ {
-		firstB(word)
+		firstB(word, nil)
 	}
 }
 
 func BenchmarkFirstB(b *testing.B) {
 	word := []byte("feed")
 	for n := 0; n < b.N; n++ {
-		firstB(word)
+		firstB(word, nil)
 	}
 }
 
 func BenchmarkFirstC(b *testing.B) {
 	word := []byte("happy")
 	for n := 0; n < b.N; n++ {
-		firstC(word)
+		firstC(word, nil)
 	}
 }
 
 func BenchmarkSecond(b *testing.B) {
 	word := []byte("vietnamization")
 	for n := 0; n < b.N; n++ {
-		second(word)
+		second(word, nil)
 	}
 }
 
 func BenchmarkThird(b *testing.B) {
 	word := []byte("electriciti")
 	for n := 0; n < b.N; n++ {
-		third(word)
+		third(word, nil)
 	}
 }
 
 func BenchmarkFour(b *testing.B) {
 	word := []byte("allowance")
 	for n := 0; n < b.N; n++ {
-		four(word)
+		four(word, nil)
 	}
 }
 
 func BenchmarkFiveA(b *testing.B) {
 	word := []byte("probate")
 	for n := 0; n < b.N; n++ {
-		firstA(word)
+		firstA(word, nil)
 	}
 }
 
 func BenchmarkFiveB(b *testing.B) {
 	word := []byte("controll")
 	for n := 0; n < b.N; n++ {
-		firstB(word)
+		firstB(word, nil)
 	}
 }
@@ -0,0 +1,196 @@
+//
+// Package stopwords provides English stop word lists and a filter removing
+// them from words or tokens, before or after stemming.
+//
+// The Snowball, SMART and Lucene lists are embedded in the package; custom
+// lists are read from files with one word per line, where everything after a
+// # or a | is a comment, so Snowball's own files can be read as they are.
+//
+package stopwords
+
+import (
+	"bufio"
+	"embed"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pigi72333/stemmer"
+	"github.com/pigi72333/stemmer/tokenizer"
+)
+
+//go:embed lists/*.txt
+var lists embed.FS
+
+//
+// List is a set of stop words, kept in lower case along with their stems.
+//
+type List struct {
+	words map[string]bool
+	stems map[string]bool
+}
+
+//
+// NewList returns a list of words.
+//
+func NewList(words ...string) *List {
+	l := &List{words: make(map[string]bool), stems: make(map[string]bool)}
+	l.Add(words...)
+	return l
+}
+
+//
+// Snowball returns the 174 English stop words of the Snowball project.
+//
+func Snowball() *List { return embedded("snowball") }
+
+//
+// SMART returns the 570 English stop words of the SMART information retrieval
+// system.
+//
+func SMART() *List { return embedded("smart") }
+
+//
+// Lucene returns the 33 English stop words of Lucene's EnglishAnalyzer.
+//
+func Lucene() *List { return embedded("lucene") }
+
+func embedded(name string) *List {
+	f, err := lists.Open("lists/" + name + ".txt")
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	l, err := Read(f)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+//
+// Load reads the list in the file at path, see Read.
+//
+func Load(path string) (*List, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+//
+// Read reads a list with one word per line. Everything after a # or a | is a
+// comment, and blank lines are skipped.
+//
+func Read(r io.Reader) (*List, error) {
+	l := NewList()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexAny(line, "#|"); i >= 0 {
+			line = line[:i]
+		}
+		if word := strings.TrimSpace(line); word != "" {
+			l.Add(word)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+//
+// Add adds words to l.
+//
+func (l *List) Add(words ...string) {
+	for _, w := range words {
+		w = strings.ToLower(w)
+		l.words[w] = true
+		l.stems[stemmer.StemString(w)] = true
+	}
+}
+
+//
+// Contains reports whether word, in any case, is in l.
+//
+func (l *List) Contains(word string) bool {
+	return l.words[strings.ToLower(word)]
+}
+
+//
+// ContainsStem reports whether stem is the stem of a word of l.
+//
+func (l *List) ContainsStem(stem string) bool {
+	return l.stems[stem]
+}
+
+//
+// Len returns the number of words of l.
+//
+func (l *List) Len() int {
+	return len(l.words)
+}
+
+//
+// Words returns the sorted words of l.
+//
+func (l *List) Words() []string {
+	words := make([]string, 0, len(l.words))
+	for w := range l.words {
+		words = append(words, w)
+	}
+	sort.Strings(words)
+	return words
+}
+
+//
+// StopFilter removes the words of a List. Set Stemmed when it runs after
+// stemming, so that stems are looked up among the stems of the list.
+//
+type StopFilter struct {
+	List    *List
+	Stemmed bool
+}
+
+//
+// Stop reports whether word is a stop word.
+//
+func (f StopFilter) Stop(word string) bool {
+	if f.Stemmed {
+		return f.List.ContainsStem(word)
+	}
+	return f.List.Contains(word)
+}
+
+//
+// Filter removes the stop words of words, in place, and returns the words
+// left.
+//
+func (f StopFilter) Filter(words []string) []string {
+	kept := words[:0]
+	for _, w := range words {
+		if !f.Stop(w) {
+			kept = append(kept, w)
+		}
+	}
+	return kept
+}
+
+//
+// FilterTokens removes the words that are stop words from tokens, in place,
+// and returns the tokens left. The tokens keep their positions, so the gaps
+// left by the stop words can still be told.
+//
+func (f StopFilter) FilterTokens(tokens []tokenizer.Token) []tokenizer.Token {
+	kept := tokens[:0]
+	for _, tok := range tokens {
+		if tok.Type != tokenizer.Word || !f.Stop(tok.Text) {
+			kept = append(kept, tok)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,71 @@
+package stopwords
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pigi72333/stemmer/tokenizer"
+)
+
+func TestEmbedded(t *testing.T) {
+	fixtures := []*List{Snowball(), SMART(), Lucene()}
+	lengths := []int{174, 570, 33}
+	words := []string{"ourselves", "whereupon", "such"}
+
+	for k, value := range fixtures {
+		if result := value.Len(); result != lengths[k] {
+			t.Errorf("List.Len() return value not what was expected, return: '%d' expected: '%d'", result, lengths[k])
+		}
+		if !value.Contains(words[k]) || !value.Contains(strings.ToUpper(words[k])) {
+			t.Errorf("List.Contains() did not find '%s'", words[k])
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	l, err := Read(strings.NewReader("# Custom list.\nfoo\n\n  Bar  | a comment\nbaz # another one\n"))
+	if err != nil {
+		t.Fatalf("Read() returned an error: '%v'", err)
+	}
+	if result, expected := l.Words(), []string{"bar", "baz", "foo"}; !reflect.DeepEqual(result, expected) {
+		t.Errorf("List.Words() return value not what was expected, return: '%q' expected: '%q'", result, expected)
+	}
+}
+
+func TestStopFilter(t *testing.T) {
+	words := []string{"The", "ponies", "were", "running", "into", "themselves"}
+	stems := []string{"the", "poni", "were", "run", "into", "themselv"}
+
+	before := StopFilter{List: Snowball()}
+	if result, expected := before.Filter(words), []string{"ponies", "running"}; !reflect.DeepEqual(result, expected) {
+		t.Errorf("StopFilter.Filter() return value not what was expected, return: '%q' expected: '%q'", result, expected)
+	}
+
+	after := StopFilter{List: Snowball(), Stemmed: true}
+	if result, expected := after.Filter(stems), []string{"poni", "run"}; !reflect.DeepEqual(result, expected) {
+		t.Errorf("StopFilter.Filter() return value not what was expected, return: '%q' expected: '%q'", result, expected)
+	}
+}
+
+func TestStopFilterTokens(t *testing.T) {
+	tok := tokenizer.New("The ponies, and the riders.")
+	tok.Stemmed = true
+	var tokens []tokenizer.Token
+	for token, ok := tok.Next(); ok; token, ok = tok.Next() {
+		tokens = append(tokens, token)
+	}
+
+	var texts []string
+	var positions []int
+	for _, token := range (StopFilter{List: Lucene(), Stemmed: true}).FilterTokens(tokens) {
+		texts = append(texts, token.Text)
+		positions = append(positions, token.Position)
+	}
+	if expected := []string{"poni", ",", "rider", "."}; !reflect.DeepEqual(texts, expected) {
+		t.Errorf("StopFilter.FilterTokens() return value not what was expected, return: '%q' expected: '%q'", texts, expected)
+	}
+	if expected := []int{1, 2, 5, 6}; !reflect.DeepEqual(positions, expected) {
+		t.Errorf("StopFilter.FilterTokens() positions not what was expected, return: '%v' expected: '%v'", positions, expected)
+	}
+}
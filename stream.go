@@ -0,0 +1,191 @@
+package stemmer
+
+import (
+	"bytes"
+	"io"
+	"unicode"
+	"unicode/utf8"
+)
+
+//
+// A word of a stream is a run of letters, combining marks included; every
+// other byte, invalid UTF-8 included, is copied as it is. Words are stemmed
+// with Stem, which also puts them in lower case. Words longer than
+// maxStreamWord bytes are copied as they are, so that the memory used does not
+// depend on the input.
+//
+const maxStreamWord = 256
+
+//
+// Writer stems the words of the text written to it and writes the result to
+// the underlying writer. Close must be called to write the last word.
+//
+type Writer struct {
+	w io.Writer
+	// word is the word being read, and long is set once it is longer than
+	// maxStreamWord and was copied to out.
+	word []byte
+	long bool
+	// partial holds the first bytes of a letter cut by the end of a Write.
+	partial []byte
+	out     []byte
+	err     error
+}
+
+//
+// NewWriter returns a Writer writing to w.
+//
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		w:       w,
+		word:    make([]byte, 0, maxStreamWord),
+		partial: make([]byte, 0, utf8.UTFMax),
+	}
+}
+
+//
+// Write stems the words of p, keeping back the word p ends with since it
+// could go on in the next Write.
+//
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n := len(p)
+	for len(w.partial) > 0 {
+		held := len(w.partial)
+		q := p
+		for len(q) > 0 && !utf8.FullRune(w.partial) {
+			w.partial = append(w.partial, q[0])
+			q = q[1:]
+		}
+		if !utf8.FullRune(w.partial) {
+			p = nil
+			break
+		}
+		// Only the letter, or the invalid byte, the bytes begin with is
+		// added: the bytes after it are read again, as they would have been
+		// had the Write not cut them.
+		_, size := utf8.DecodeRune(w.partial)
+		w.add(w.partial[:size])
+		if size >= held {
+			p = p[size-held:]
+			w.partial = w.partial[:0]
+		} else {
+			w.partial = append(w.partial[:0], w.partial[size:held]...)
+		}
+	}
+	for len(p) > 0 {
+		size := 1
+		if p[0] >= utf8.RuneSelf {
+			if !utf8.FullRune(p) {
+				w.partial = append(w.partial, p...)
+				break
+			}
+			_, size = utf8.DecodeRune(p)
+		}
+		w.add(p[:size])
+		p = p[size:]
+	}
+	if err := w.flush(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+//
+// Close writes the last word. It does not close the underlying writer.
+//
+func (w *Writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	w.endWord()
+	w.out = append(w.out, w.partial...)
+	w.partial = w.partial[:0]
+	return w.flush()
+}
+
+//
+// add adds the letter or byte c to the output.
+//
+func (w *Writer) add(c []byte) {
+	if !streamLetter(c) {
+		w.endWord()
+		w.out = append(w.out, c...)
+		return
+	}
+	if w.long {
+		w.out = append(w.out, c...)
+		return
+	}
+	if len(w.word)+len(c) > maxStreamWord {
+		w.out = append(append(w.out, w.word...), c...)
+		w.word = w.word[:0]
+		w.long = true
+		return
+	}
+	w.word = append(w.word, c...)
+}
+
+func (w *Writer) endWord() {
+	if len(w.word) > 0 {
+		w.out = AppendStem(w.out, w.word)
+		w.word = w.word[:0]
+	}
+	w.long = false
+}
+
+func (w *Writer) flush() error {
+	if len(w.out) == 0 {
+		return nil
+	}
+	_, err := w.w.Write(w.out)
+	w.out = w.out[:0]
+	if err != nil {
+		w.err = err
+	}
+	return err
+}
+
+func streamLetter(c []byte) bool {
+	if c[0] < utf8.RuneSelf {
+		return 'a' <= c[0] && c[0] <= 'z' || 'A' <= c[0] && c[0] <= 'Z'
+	}
+	r, _ := utf8.DecodeRune(c)
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.Is(unicode.Mn, r))
+}
+
+//
+// StemReader returns a reader of the text of r with its words stemmed, see
+// Writer.
+//
+func StemReader(r io.Reader) io.Reader {
+	s := &stemReader{r: r, in: make([]byte, 32*1024)}
+	s.w = NewWriter(&s.out)
+	return s
+}
+
+type stemReader struct {
+	r   io.Reader
+	w   *Writer
+	in  []byte
+	out bytes.Buffer
+	err error
+}
+
+func (s *stemReader) Read(p []byte) (int, error) {
+	for s.out.Len() == 0 && s.err == nil {
+		n, err := s.r.Read(s.in)
+		// Writing to s.out does not fail.
+		s.w.Write(s.in[:n])
+		if err == io.EOF {
+			s.w.Close()
+		}
+		s.err = err
+	}
+	if s.out.Len() > 0 {
+		return s.out.Read(p)
+	}
+	return 0, s.err
+}
@@ -0,0 +1,120 @@
+package stemmer
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestStemReader(t *testing.T) {
+	fixtures := []string{
+		"",
+		"Conflated feeds, hopping...",
+		"  The ponies were running\n\tin the fields!",
+		"naïve résumés (café)",
+		"\xffcats\xc3 dogs",
+		strings.Repeat("a", maxStreamWord+10) + "s cats",
+	}
+
+	stemmed := []string{
+		"",
+		"conflat feed, hop...",
+		"  the poni were run\n\tin the field!",
+		"naïv résumé (café)",
+		"\xffcat\xc3 dog",
+		strings.Repeat("a", maxStreamWord+10) + "s cat",
+	}
+
+	for k, value := range fixtures {
+		result, err := ioutil.ReadAll(StemReader(strings.NewReader(value)))
+		if err != nil || string(result) != stemmed[k] {
+			t.Errorf("StemReader() return value not what was expected, pass: '%q' return: '%q' error: '%v' expected: '%q'", value, result, err, stemmed[k])
+		}
+		// One byte at a time cuts the words and the letters anywhere.
+		result, err = ioutil.ReadAll(StemReader(iotest.OneByteReader(strings.NewReader(value))))
+		if err != nil || string(result) != stemmed[k] {
+			t.Errorf("StemReader() return value not what was expected, pass: '%q' return: '%q' error: '%v' expected: '%q'", value, result, err, stemmed[k])
+		}
+	}
+}
+
+func TestWriterVocal(t *testing.T) {
+	v, err := os.Open("voc.txt")
+	if err != nil {
+		panic(err)
+	}
+	defer v.Close()
+	expected, err := ioutil.ReadFile("output.txt")
+	if err != nil {
+		panic(err)
+	}
+
+	var out bytes.Buffer
+	w := NewWriter(&out)
+	if _, err := io.Copy(w, v); err != nil {
+		t.Fatalf("Writer.Write() returned an error: '%v'", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Writer.Close() returned an error: '%v'", err)
+	}
+	if !bytes.Equal(out.Bytes(), expected) {
+		t.Errorf("Writer output not what was expected, return: %d bytes expected: %d bytes", out.Len(), len(expected))
+	}
+}
+
+func TestWriterSplit(t *testing.T) {
+	fixtures := []string{
+		"naïve résumés (café)",
+		"x\xc3abilities done",
+		"\xe2\x82abilities \xf0\x9fcats\xe2\x82\xac",
+		"\xffcats\xc3 dogs\xc3",
+	}
+
+	stemmed := []string{
+		"naïv résumé (café)",
+		"x\xc3abil done",
+		"\xe2\x82abil \xf0\x9fcat\xe2\x82\xac",
+		"\xffcat\xc3 dog\xc3",
+	}
+
+	// The output does not depend on where the Writes cut the text.
+	for k, value := range fixtures {
+		for i := 0; i <= len(value); i++ {
+			var out bytes.Buffer
+			w := NewWriter(&out)
+			w.Write([]byte(value[:i]))
+			w.Write([]byte(value[i:]))
+			w.Close()
+			if out.String() != stemmed[k] {
+				t.Errorf("Writer output not what was expected, pass: '%q' cut at: %d return: '%q' expected: '%q'", value, i, out.String(), stemmed[k])
+			}
+		}
+	}
+}
+
+func TestWriterError(t *testing.T) {
+	w := NewWriter(failWriter{})
+	if _, err := w.Write([]byte("cats and dogs")); err != io.ErrShortWrite {
+		t.Errorf("Writer.Write() error not what was expected, return: '%v' expected: '%v'", err, io.ErrShortWrite)
+	}
+	if err := w.Close(); err != io.ErrShortWrite {
+		t.Errorf("Writer.Close() error not what was expected, return: '%v' expected: '%v'", err, io.ErrShortWrite)
+	}
+}
+
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) { return 0, io.ErrShortWrite }
+
+func BenchmarkStemReader(b *testing.B) {
+	text := bytes.Repeat([]byte("The ponies were running in the fields, conflated and hopping. "), 1000)
+	b.SetBytes(int64(len(text)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		io.Copy(ioutil.Discard, StemReader(bytes.NewReader(text)))
+	}
+}
@@ -0,0 +1,48 @@
+package stemmer
+
+//
+// The Swedish stemmer:
+//
+//    https://snowballstem.org/algorithms/swedish/stemmer.html
+//
+
+const swedishVowels = "aeiouyäåö"
+
+var swedishMainSuffixes = newSuffixList(
+	"a", "arna", "erna", "heterna", "orna", "ad", "e", "ade", "ande", "arne",
+	"are", "aste", "en", "anden", "aren", "heten", "ern", "ar", "er", "heter",
+	"or", "as", "arnas", "ernas", "ornas", "es", "ades", "andes", "ens",
+	"arens", "hetens", "erns", "at", "andet", "het", "ast", "s",
+)
+
+var swedishOtherSuffixes = newSuffixList("lig", "ig", "els", "löst", "fullt")
+
+func stemSwedish(w []rune) []rune {
+	r1 := scandinavianR1(w, swedishVowels)
+
+	// Step 1: the main suffixes in R1; s only after a valid s-ending.
+	if s, start := swedishMainSuffixes.find(w, r1); s == "s" {
+		if inGroup(w, start-1, "bcdfghjklmnoprtvy") {
+			w = w[:start]
+		}
+	} else if s != "" {
+		w = w[:start]
+	}
+
+	// Step 2: undouble dd, gd, nn, dt, gt, kt and tt in R1.
+	for _, s := range []string{"dd", "gd", "nn", "dt", "gt", "kt", "tt"} {
+		if suffixStart(w, s) >= r1 {
+			w = w[:len(w)-1]
+			break
+		}
+	}
+
+	// Step 3: the other suffixes in R1; löst and fullt lose their t.
+	switch s, start := swedishOtherSuffixes.find(w, r1); s {
+	case "lig", "ig", "els":
+		w = w[:start]
+	case "löst", "fullt":
+		w = w[:len(w)-1]
+	}
+	return w
+}
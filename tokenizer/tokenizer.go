@@ -0,0 +1,266 @@
+//
+// Package tokenizer splits UTF-8 text into positioned tokens: words, numbers,
+// punctuation, URLs, email addresses and hashtags.
+//
+// A word is a run of letters, digits and combining marks containing at least
+// one letter. An apostrophe followed by a letter stays in the word, so "don't"
+// and "John's" are single words, and so does a hyphen between two parts that
+// are not both numbers, so "state-of-the-art" and "covid-19" are too. A number
+// is a run of digits, with the "." and "," between digits, e.g. "1,000.50".
+// Every other character that is not a space is a punctuation token of its
+// own.
+//
+package tokenizer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pigi72333/stemmer"
+)
+
+//
+// Type is the type of a token.
+//
+type Type int
+
+const (
+	Word Type = iota
+	Number
+	Punctuation
+	URL
+	Email
+	Hashtag
+)
+
+var typeNames = []string{"word", "number", "punctuation", "url", "email", "hashtag"}
+
+func (t Type) String() string {
+	if t < 0 || int(t) >= len(typeNames) {
+		return "unknown"
+	}
+	return typeNames[t]
+}
+
+//
+// Token is a token of a text.
+//
+type Token struct {
+	// Text is the text of the token, or its stem for the words of a Stemmed
+	// Tokenizer.
+	Text string
+	// Start and End are the byte offsets of the token in the text, so the
+	// token as written is always text[Start:End].
+	Start, End int
+	// Position is the index of the token among the tokens of the text.
+	Position int
+	Type     Type
+}
+
+//
+// Tokenizer returns the tokens of a text one at a time.
+//
+type Tokenizer struct {
+	// Stemmed replaces the Text of the words by their Porter stem. The parts
+	// of a hyphenated word are stemmed one by one, and an English possessive
+	// "'s" is dropped before stemming.
+	Stemmed bool
+
+	text string
+	pos  int
+	n    int
+}
+
+//
+// New returns a Tokenizer for text.
+//
+func New(text string) *Tokenizer {
+	return &Tokenizer{text: text}
+}
+
+//
+// Tokenize returns the tokens of text.
+//
+func Tokenize(text string) []Token {
+	var tokens []Token
+	t := New(text)
+	for tok, ok := t.Next(); ok; tok, ok = t.Next() {
+		tokens = append(tokens, tok)
+	}
+	return tokens
+}
+
+//
+// Next returns the next token, or false at the end of the text.
+//
+func (t *Tokenizer) Next() (Token, bool) {
+	for t.pos < len(t.text) {
+		r, size := utf8.DecodeRuneInString(t.text[t.pos:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		t.pos += size
+	}
+	if t.pos == len(t.text) {
+		return Token{}, false
+	}
+
+	rest := t.text[t.pos:]
+	_, size := utf8.DecodeRuneInString(rest)
+	typ := Punctuation
+	if n := urlLength(rest); n > 0 {
+		typ, size = URL, n
+	} else if n := emailLength(rest); n > 0 {
+		typ, size = Email, n
+	} else if n := hashtagLength(rest); n > 0 {
+		typ, size = Hashtag, n
+	} else if n, letters := wordLength(rest); n > 0 {
+		typ, size = Number, n
+		if letters {
+			typ = Word
+		}
+	}
+
+	tok := Token{
+		Text:     rest[:size],
+		Start:    t.pos,
+		End:      t.pos + size,
+		Position: t.n,
+		Type:     typ,
+	}
+	if t.Stemmed && typ == Word {
+		tok.Text = stem(tok.Text)
+	}
+	t.pos += size
+	t.n++
+	return tok, true
+}
+
+func stem(word string) string {
+	parts := strings.Split(word, "-")
+	for i, part := range parts {
+		for _, s := range []string{"'s", "’s", "'S", "’S"} {
+			if len(part) > len(s) && strings.HasSuffix(part, s) {
+				part = part[:len(part)-len(s)]
+				break
+			}
+		}
+		parts[i] = stemmer.StemString(part)
+	}
+	return strings.Join(parts, "-")
+}
+
+//
+// urlLength returns the length of the URL s starts with, or 0. A URL starts
+// with http://, https:// or www. and ends before the first space, without
+// the punctuation that ends a sentence or closes a parenthesis.
+//
+func urlLength(s string) int {
+	prefix := 0
+	for _, p := range []string{"http://", "https://", "www."} {
+		if len(s) > len(p) && strings.EqualFold(s[:len(p)], p) {
+			prefix = len(p)
+			break
+		}
+	}
+	if prefix == 0 {
+		return 0
+	}
+	n := len(s)
+	for i, r := range s {
+		if unicode.IsSpace(r) {
+			n = i
+			break
+		}
+	}
+	n = len(strings.TrimRight(s[:n], ".,;:!?'\")]}>"))
+	if n <= prefix {
+		return 0
+	}
+	return n
+}
+
+//
+// emailLength returns the length of the email address s starts with, or 0.
+//
+func emailLength(s string) int {
+	i := 0
+	for i < len(s) && (isAlnum(s[i]) || strings.IndexByte("._%+-", s[i]) >= 0) {
+		i++
+	}
+	if i == 0 || !isAlnum(s[0]) || i == len(s) || s[i] != '@' {
+		return 0
+	}
+	at := i
+	for i++; i < len(s) && (isAlnum(s[i]) || s[i] == '.' || s[i] == '-'); i++ {
+	}
+	domain := strings.TrimRight(s[at+1:i], ".-")
+	dot := strings.LastIndexByte(domain, '.')
+	if dot <= 0 || dot == len(domain)-1 || !isAlnum(domain[0]) {
+		return 0
+	}
+	return at + 1 + len(domain)
+}
+
+func isAlnum(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+//
+// hashtagLength returns the length of the hashtag s starts with, or 0.
+//
+func hashtagLength(s string) int {
+	if len(s) < 2 || s[0] != '#' {
+		return 0
+	}
+	letters := false
+	n := 1
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if unicode.IsLetter(r) {
+			letters = true
+		} else if !unicode.IsDigit(r) && r != '_' && !unicode.Is(unicode.Mn, r) {
+			break
+		}
+		n += size
+	}
+	if !letters {
+		return 0
+	}
+	return n
+}
+
+//
+// wordLength returns the length of the word or number s starts with, or 0,
+// and whether it has letters.
+//
+func wordLength(s string) (int, bool) {
+	n := 0
+	letters := false
+	digits := false
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		switch {
+		case unicode.IsLetter(r):
+			letters = true
+		case unicode.IsDigit(r):
+			digits = true
+		case unicode.Is(unicode.Mn, r) && n > 0:
+		case n == 0:
+			return 0, false
+		default:
+			next, _ := utf8.DecodeRuneInString(s[n+size:])
+			switch {
+			case (r == '\'' || r == '’') && unicode.IsLetter(next):
+			case r == '-' && unicode.IsLetter(next):
+			case r == '-' && unicode.IsDigit(next) && letters:
+			case (r == '.' || r == ',') && unicode.IsDigit(next) && digits && !letters:
+			default:
+				return n, letters
+			}
+		}
+		n += size
+	}
+	return n, letters
+}
@@ -0,0 +1,111 @@
+package tokenizer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	text := "Don't email john.doe@example.com: see https://example.com/a?b=1, #NLP 2019-2020 costs $1,000.50 for state-of-the-art covid-19 work!"
+
+	expected := []Token{
+		{Text: "Don't", Start: 0, End: 5, Position: 0, Type: Word},
+		{Text: "email", Start: 6, End: 11, Position: 1, Type: Word},
+		{Text: "john.doe@example.com", Start: 12, End: 32, Position: 2, Type: Email},
+		{Text: ":", Start: 32, End: 33, Position: 3, Type: Punctuation},
+		{Text: "see", Start: 34, End: 37, Position: 4, Type: Word},
+		{Text: "https://example.com/a?b=1", Start: 38, End: 63, Position: 5, Type: URL},
+		{Text: ",", Start: 63, End: 64, Position: 6, Type: Punctuation},
+		{Text: "#NLP", Start: 65, End: 69, Position: 7, Type: Hashtag},
+		{Text: "2019", Start: 70, End: 74, Position: 8, Type: Number},
+		{Text: "-", Start: 74, End: 75, Position: 9, Type: Punctuation},
+		{Text: "2020", Start: 75, End: 79, Position: 10, Type: Number},
+		{Text: "costs", Start: 80, End: 85, Position: 11, Type: Word},
+		{Text: "$", Start: 86, End: 87, Position: 12, Type: Punctuation},
+		{Text: "1,000.50", Start: 87, End: 95, Position: 13, Type: Number},
+		{Text: "for", Start: 96, End: 99, Position: 14, Type: Word},
+		{Text: "state-of-the-art", Start: 100, End: 116, Position: 15, Type: Word},
+		{Text: "covid-19", Start: 117, End: 125, Position: 16, Type: Word},
+		{Text: "work", Start: 126, End: 130, Position: 17, Type: Word},
+		{Text: "!", Start: 130, End: 131, Position: 18, Type: Punctuation},
+	}
+
+	tokens := Tokenize(text)
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("Tokenize() return value not what was expected, pass: '%s' return: '%+v' expected: '%+v'", text, tokens, expected)
+	}
+	for _, tok := range tokens {
+		if text[tok.Start:tok.End] != tok.Text {
+			t.Errorf("Tokenize() offsets do not match the text, token: '%+v' text: '%s'", tok, text[tok.Start:tok.End])
+		}
+	}
+}
+
+func TestTokenizeApostrophes(t *testing.T) {
+	fixtures := []string{
+		"John's ponies",
+		"the students' café",
+		"rock’n’roll",
+		"'quoted'",
+		"see www.example.org.",
+	}
+
+	texts := [][]string{
+		{"John's", "ponies"},
+		{"the", "students", "'", "café"},
+		{"rock’n’roll"},
+		{"'", "quoted", "'"},
+		{"see", "www.example.org", "."},
+	}
+
+	for k, value := range fixtures {
+		var result []string
+		for _, tok := range Tokenize(value) {
+			result = append(result, tok.Text)
+		}
+		if !reflect.DeepEqual(result, texts[k]) {
+			t.Errorf("Tokenize() return value not what was expected, pass: '%s' return: '%q' expected: '%q'", value, result, texts[k])
+		}
+	}
+}
+
+func TestTokenizerStemmed(t *testing.T) {
+	text := "The ponies’ riders were running John's well-known races, #running 42."
+
+	expected := []Token{
+		{Text: "the", Start: 0, End: 3, Position: 0, Type: Word},
+		{Text: "poni", Start: 4, End: 10, Position: 1, Type: Word},
+		{Text: "’", Start: 10, End: 13, Position: 2, Type: Punctuation},
+		{Text: "rider", Start: 14, End: 20, Position: 3, Type: Word},
+		{Text: "were", Start: 21, End: 25, Position: 4, Type: Word},
+		{Text: "run", Start: 26, End: 33, Position: 5, Type: Word},
+		{Text: "john", Start: 34, End: 40, Position: 6, Type: Word},
+		{Text: "well-known", Start: 41, End: 51, Position: 7, Type: Word},
+		{Text: "race", Start: 52, End: 57, Position: 8, Type: Word},
+		{Text: ",", Start: 57, End: 58, Position: 9, Type: Punctuation},
+		{Text: "#running", Start: 59, End: 67, Position: 10, Type: Hashtag},
+		{Text: "42", Start: 68, End: 70, Position: 11, Type: Number},
+		{Text: ".", Start: 70, End: 71, Position: 12, Type: Punctuation},
+	}
+
+	var tokens []Token
+	tok := New(text)
+	tok.Stemmed = true
+	for token, ok := tok.Next(); ok; token, ok = tok.Next() {
+		tokens = append(tokens, token)
+	}
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("Tokenizer.Next() return value not what was expected, pass: '%s' return: '%+v' expected: '%+v'", text, tokens, expected)
+	}
+}
+
+func TestTypeString(t *testing.T) {
+	fixtures := []Type{Word, Number, Punctuation, URL, Email, Hashtag, Type(42)}
+	names := []string{"word", "number", "punctuation", "url", "email", "hashtag", "unknown"}
+
+	for k, value := range fixtures {
+		if result := value.String(); result != names[k] {
+			t.Errorf("Type.String() return value not what was expected, pass: '%d' return: '%s' expected: '%s'", value, result, names[k])
+		}
+	}
+}
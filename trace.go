@@ -0,0 +1,84 @@
+package stemmer
+
+//
+// StepResult describes a rule of the Porter algorithm that matched a word, as
+// reported by StemTrace.
+//
+type StepResult struct {
+	// Step is the step the rule belongs to: 1a, 1b, 1b2 (the second part of
+	// Step 1b), 1c, 2, 3, 4, 5a or 5b.
+	Step string
+	// Suffix is the suffix the rule matched. It is empty for the rules of
+	// steps 1b2 and 5b that only look at the stem.
+	Suffix string
+	// Measure is m of the word without Suffix.
+	Measure int
+	// Condition is the condition of the rule in the notation of the paper,
+	// e.g. "m>0", "*v*" or "m>1 and (*S or *T)", or empty if there is none.
+	Condition string
+	// Before and After are the word before and after the step. They are
+	// equal when the condition did not hold.
+	Before []byte
+	After  []byte
+}
+
+//
+// StemTrace returns the stem of word, as Stem does, along with the rules that
+// matched it in the order the steps tried them.
+//
+func StemTrace(word []byte) (stem []byte, steps []StepResult) {
+	return porterRules.StemTrace(word)
+}
+
+//
+// trace collects the StepResults of StemTrace. The step functions receive a
+// nil *trace from Stem, so all its methods must accept a nil receiver.
+//
+type trace struct {
+	step  string
+	steps []StepResult
+	// pending is the index of the result still waiting for its After, or -1.
+	pending int
+}
+
+//
+// begin starts the step named step on word. The result of the previous step
+// is complete by then, even when the previous step is the one running this
+// one, like 1b does with 1b2.
+//
+func (t *trace) begin(step string, word []byte) {
+	if t == nil {
+		return
+	}
+	t.done(word)
+	t.step = step
+}
+
+//
+// match records that the rule of the current step with a suffix of the last n
+// bytes of word and the given condition matched word.
+//
+func (t *trace) match(word []byte, n int, condition string) {
+	if t == nil {
+		return
+	}
+	l := len(word)
+	t.steps = append(t.steps, StepResult{
+		Step:      t.step,
+		Suffix:    string(word[l-n:]),
+		Measure:   measure(word[:l-n]),
+		Condition: condition,
+		Before:    append([]byte{}, word...),
+	})
+	t.pending = len(t.steps) - 1
+}
+
+//
+// done completes the pending result, if any, with the word it produced.
+//
+func (t *trace) done(word []byte) {
+	if t != nil && t.pending >= 0 {
+		t.steps[t.pending].After = append([]byte{}, word...)
+		t.pending = -1
+	}
+}
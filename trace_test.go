@@ -0,0 +1,65 @@
+package stemmer
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestStemTrace(t *testing.T) {
+	fixtures := []word{
+		[]byte("conflated"),
+		[]byte("feed"),
+		[]byte("hopping"),
+	}
+
+	traces := [][]StepResult{
+		{
+			{Step: "1b", Suffix: "ed", Measure: 2, Condition: "*v*", Before: []byte("conflated"), After: []byte("conflat")},
+			{Step: "1b2", Suffix: "at", Measure: 1, Condition: "", Before: []byte("conflat"), After: []byte("conflate")},
+			{Step: "4", Suffix: "ate", Measure: 1, Condition: "m>1", Before: []byte("conflate"), After: []byte("conflate")},
+			{Step: "5a", Suffix: "e", Measure: 2, Condition: "m>1 or (m=1 and not *o)", Before: []byte("conflate"), After: []byte("conflat")},
+		},
+		{
+			{Step: "1b", Suffix: "eed", Measure: 0, Condition: "m>0", Before: []byte("feed"), After: []byte("feed")},
+		},
+		{
+			{Step: "1b", Suffix: "ing", Measure: 1, Condition: "*v*", Before: []byte("hopping"), After: []byte("hopp")},
+			{Step: "1b2", Suffix: "pp", Measure: 0, Condition: "", Before: []byte("hopp"), After: []byte("hop")},
+		},
+	}
+
+	for k, value := range fixtures {
+		if _, steps := StemTrace(value); !reflect.DeepEqual(steps, traces[k]) {
+			t.Errorf("StemTrace() return value not what was expected, pass: '%s' return: '%+v' expected: '%+v'", value, steps, traces[k])
+		}
+	}
+}
+
+func TestStemTraceVocal(t *testing.T) {
+	v, err := os.Open("voc.txt")
+	if err != nil {
+		panic(err)
+	}
+	defer v.Close()
+	vocScanner := bufio.NewScanner(v)
+
+	for vocScanner.Scan() {
+		word := vocScanner.Bytes()
+		stem, steps := StemTrace(word)
+		if expected := Stem(word); !bytes.Equal(stem, expected) {
+			t.Errorf("StemTrace() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", word, stem, expected)
+		}
+		// Every step starts from what the previous one left.
+		for i := 1; i < len(steps); i++ {
+			if !bytes.Equal(steps[i].Before, steps[i-1].After) {
+				t.Errorf("StemTrace() steps do not chain, pass: '%s' step %s after: '%s' step %s before: '%s'", word, steps[i-1].Step, steps[i-1].After, steps[i].Step, steps[i].Before)
+			}
+		}
+		if len(steps) > 0 && !bytes.Equal(steps[len(steps)-1].After, stem) {
+			t.Errorf("StemTrace() last step does not end with the stem, pass: '%s' return: '%s' expected: '%s'", word, steps[len(steps)-1].After, stem)
+		}
+	}
+}
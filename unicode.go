@@ -0,0 +1,117 @@
+package stemmer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+//
+// Words that are not ASCII are put in lower case and in NFC before stemming,
+// with golang.org/x/text/unicode/norm: a letter followed by a combining accent
+// is replaced by the precomposed letter, so "café" and "cafe\u0301" have the
+// same stem, and bytes that are not valid UTF-8 are replaced by U+FFFD.
+//
+// The letters whose canonical decomposition starts with A, E, I, O or U are
+// vowels, as are Æ, Œ and Ø, and every other letter is a consonant. Since
+// every suffix of the rules is ASCII the stem of a valid word is always valid
+// UTF-8.
+//
+// FoldAccents goes further and spells the Latin letters in ASCII, which is
+// what Porter{FoldAccents: true} does before stemming.
+//
+
+//
+// latinFolds spell in ASCII the Latin letters that NFKD does not decompose
+// into an ASCII letter and combining marks.
+//
+var latinFolds = map[rune]string{
+	'Æ': "AE", 'æ': "ae", 'Ð': "D", 'ð': "d", 'Ø': "O", 'ø': "o", 'Þ': "TH", 'þ': "th",
+	'ß': "ss", 'Đ': "D", 'đ': "d", 'Ħ': "H", 'ħ': "h", 'ı': "i", 'Ĳ': "IJ", 'ĳ': "ij",
+	'Ŀ': "L", 'ŀ': "l", 'Ł': "L", 'ł': "l", 'ŉ': "n", 'Ŋ': "NG", 'ŋ': "ng", 'Œ': "OE",
+	'œ': "oe", 'Ŧ': "T", 'ŧ': "t", 'ſ': "s",
+}
+
+//
+// appendNormalized appends word to dst in lower case and in NFC, with the
+// bytes that are not valid UTF-8 replaced by U+FFFD.
+//
+func appendNormalized(dst []byte, word string) []byte {
+	// dst is not passed to norm, which would make it escape and the buffers
+	// of the ASCII words allocate.
+	return append(dst, norm.NFC.String(strings.Map(unicode.ToLower, word))...)
+}
+
+//
+// FoldAccents returns word with the accents of the Latin letters removed and
+// the other letters and ligatures spelled in ASCII, e.g. "Crème Brûlée" is
+// "Creme Brulee" and "Æsop" is "AEsop". Combining marks are dropped and bytes
+// that are not valid UTF-8 are replaced by U+FFFD. word is never modified.
+//
+func FoldAccents(word []byte) []byte {
+	return appendFolded(make([]byte, 0, len(word)), string(word))
+}
+
+func appendFolded(dst []byte, word string) []byte {
+	var buf [utf8.UTFMax]byte
+	for _, r := range word {
+		switch s, ok := latinFolds[r]; {
+		case r < utf8.RuneSelf:
+			dst = append(dst, byte(r))
+		case ok:
+			dst = append(dst, s...)
+		case unicode.Is(unicode.Latin, r):
+			// NFKD splits the letter from its accents, and the ligatures
+			// into their letters.
+			n := utf8.EncodeRune(buf[:], r)
+			for _, d := range string(norm.NFKD.Bytes(buf[:n])) {
+				if !unicode.Is(unicode.Mn, d) {
+					dst = appendRune(dst, d)
+				}
+			}
+		case !unicode.Is(unicode.Mn, r):
+			dst = appendRune(dst, r)
+		}
+	}
+	return dst
+}
+
+func appendRune(dst []byte, r rune) []byte {
+	var buf [utf8.UTFMax]byte
+	n := utf8.EncodeRune(buf[:], r)
+	return append(dst, buf[:n]...)
+}
+
+//
+// vowelRune reports whether r is A, E, I, O or U, with or without an accent,
+// or one of Æ, Œ and Ø.
+//
+func vowelRune(r rune) bool {
+	if s, ok := latinFolds[r]; ok {
+		r = rune(s[0])
+	} else {
+		var buf [utf8.UTFMax]byte
+		n := utf8.EncodeRune(buf[:], r)
+		if d := norm.NFD.Properties(buf[:n]).Decomposition(); d != nil {
+			r, _ = utf8.DecodeRune(d)
+		}
+	}
+	switch unicode.ToLower(r) {
+	case 'a', 'e', 'i', 'o', 'u':
+		return true
+	}
+	return false
+}
+
+//
+// letterStart returns the index of the first byte of the letter word[i] is
+// part of.
+//
+func letterStart(word []byte, i int) int {
+	for i > 0 && !utf8.RuneStart(word[i]) {
+		i--
+	}
+	return i
+}
@@ -0,0 +1,154 @@
+package stemmer
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestStemUnicode(t *testing.T) {
+	fixtures := []string{
+		"naïve",
+		"Résumés",
+		"fiancées",
+		"protégés",
+		"débâcles",
+		"señoritas",
+		"œuvres",
+		"smörgåsbords",
+		"façades",
+		"naïvety",
+	}
+
+	stemmed := []string{
+		"naïv",
+		"résumé",
+		"fiancé",
+		"protégé",
+		"débâcl",
+		"señorita",
+		"œuvr",
+		"smörgåsbord",
+		"façad",
+		"naïveti",
+	}
+
+	folded := []string{
+		"naiv",
+		"resum",
+		"fiance",
+		"proteg",
+		"debacl",
+		"senorita",
+		"oeuvr",
+		"smorgasbord",
+		"facad",
+		"naiveti",
+	}
+
+	for k, value := range fixtures {
+		if result := StemString(value); result != stemmed[k] {
+			t.Errorf("StemString() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, stemmed[k])
+		}
+		if result := (Porter{FoldAccents: true}).StemString(value); result != folded[k] {
+			t.Errorf("Porter.StemString() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, folded[k])
+		}
+	}
+}
+
+func TestStemNFC(t *testing.T) {
+	fixtures := []string{
+		"cafe\u0301s",
+		"re\u0301sume\u0301s",
+		"nai\u0308ve",
+		"NAI\u0308VE",
+	}
+
+	stemmed := []string{
+		"café",
+		"résumé",
+		"naïv",
+		"naïv",
+	}
+
+	for k, value := range fixtures {
+		if result := StemString(value); result != stemmed[k] {
+			t.Errorf("StemString() return value not what was expected, pass: '%+q' return: '%+q' expected: '%+q'", value, result, stemmed[k])
+		}
+	}
+}
+
+func TestMeasureUnicode(t *testing.T) {
+	fixtures := []word{
+		[]byte("naïv"),
+		[]byte("résumé"),
+		[]byte("œuvr"),
+		[]byte("señor"),
+	}
+
+	measures := []int{
+		1,
+		2,
+		1,
+		2,
+	}
+
+	for k, value := range fixtures {
+		if result := measure(value); result != measures[k] {
+			t.Errorf("measure() return value not what was expected, pass: '%s' return: '%d' expected: '%d'", value, result, measures[k])
+		}
+	}
+}
+
+func TestFoldAccents(t *testing.T) {
+	fixtures := []string{
+		"Crème Brûlée",
+		"Æsop",
+		"straße",
+		"ﬁnal",
+		"café",
+		"日本",
+		"Ǻngström",
+		"Ｌａｔｉｎ",
+		"がっこう",
+	}
+
+	folded := []string{
+		"Creme Brulee",
+		"AEsop",
+		"strasse",
+		"final",
+		"cafe",
+		"日本",
+		"Angstrom",
+		"Latin",
+		"がっこう",
+	}
+
+	for k, value := range fixtures {
+		if result := string(FoldAccents([]byte(value))); result != folded[k] {
+			t.Errorf("FoldAccents() return value not what was expected, pass: '%s' return: '%s' expected: '%s'", value, result, folded[k])
+		}
+	}
+}
+
+func TestStemValidUTF8(t *testing.T) {
+	pieces := []string{"a", "e", "s", "y", "ing", "ed", "é", "ï", "ß", "ø", "\u0301", "日", "\xff", "\xc3", "\xe6\x97"}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		var w []byte
+		for n := r.Intn(8); n >= 0; n-- {
+			w = append(w, pieces[r.Intn(len(pieces))]...)
+		}
+		if result := Stem(w); !utf8.Valid(result) {
+			t.Errorf("Stem() returned invalid UTF-8, pass: '%+q' return: '%+q'", w, result)
+		}
+		if result := StemPorter2(w); !utf8.Valid(result) {
+			t.Errorf("StemPorter2() returned invalid UTF-8, pass: '%+q' return: '%+q'", w, result)
+		}
+		if result := FoldAccents(w); !utf8.Valid(result) || bytes.ContainsRune(result, '\u0301') {
+			t.Errorf("FoldAccents() return value not what was expected, pass: '%+q' return: '%+q'", w, result)
+		}
+	}
+}